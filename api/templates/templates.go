@@ -0,0 +1,9 @@
+// Package templates holds the HTML templates of the api, embedded into the binary.
+package templates
+
+import "embed"
+
+// FS contains the templates, named after their files without the extension.
+//
+//go:embed *.tmpl
+var FS embed.FS
@@ -22,16 +22,44 @@ func main() {
 		return
 	}
 
+	timerPolicy := os.Getenv("TIMER_POLICY")
+	switch timerPolicy {
+	case "":
+		timerPolicy = config.TIMER_POLICY_PARALLEL
+	case config.TIMER_POLICY_PARALLEL, config.TIMER_POLICY_REJECT, config.TIMER_POLICY_SWITCH:
+	default:
+		logger.Log.WithFields(logrus.Fields{
+			"timerPolicy": timerPolicy,
+		}).Error("Unknown TIMER_POLICY in .env. Use PARALLEL, REJECT or SWITCH.")
+		return
+	}
+
+	overlapPolicy := os.Getenv("OVERLAP_POLICY")
+	switch overlapPolicy {
+	case "":
+		overlapPolicy = config.OVERLAP_POLICY_FLAG
+	case config.OVERLAP_POLICY_FLAG, config.OVERLAP_POLICY_REJECT:
+	default:
+		logger.Log.WithFields(logrus.Fields{
+			"overlapPolicy": overlapPolicy,
+		}).Error("Unknown OVERLAP_POLICY in .env. Use FLAG or REJECT.")
+		return
+	}
+
 	config := config.ApiEnvConfig{
-		Port:        os.Getenv("PORT"),
-		Env:         os.Getenv("ENV"),
-		Host:        os.Getenv("HOST"),
-		AuthService: os.Getenv("AUTH_URL"),
+		Port:          os.Getenv("PORT"),
+		Env:           os.Getenv("ENV"),
+		Host:          os.Getenv("HOST"),
+		AuthService:   os.Getenv("AUTH_URL"),
+		TimerPolicy:   timerPolicy,
+		OverlapPolicy: overlapPolicy,
 	}
 
 	logger.Log.WithFields(logrus.Fields{
-		"port": config.Port,
-		"host": config.Host,
+		"port":          config.Port,
+		"host":          config.Host,
+		"timerPolicy":   config.TimerPolicy,
+		"overlapPolicy": config.OverlapPolicy,
 	}).Info("Loaded app config")
 
 	var wg sync.WaitGroup
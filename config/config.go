@@ -1,12 +1,25 @@
 package config
 
 type ApiEnvConfig struct {
-	Port        string
-	Env         string
-	Host        string
-	AuthService string
+	Port          string
+	Env           string
+	Host          string
+	AuthService   string
+	TimerPolicy   string
+	OverlapPolicy string
 }
 
 const DEV_ENV = "DEV"
 const STAGE_ENV = "STAGE"
 const PROD_ENV = "PROD"
+
+// Policies for the timers a user runs at the same time. PARALLEL allows any number of them,
+// REJECT refuses to start a timer while another one runs and SWITCH stops the running timers.
+const TIMER_POLICY_PARALLEL = "PARALLEL"
+const TIMER_POLICY_REJECT = "REJECT"
+const TIMER_POLICY_SWITCH = "SWITCH"
+
+// Policies for sessions of a user that overlap in time. FLAG saves them and marks them as overlapping,
+// REJECT refuses created, edited or imported sessions that overlap. Stopped timers are always only flagged.
+const OVERLAP_POLICY_FLAG = "FLAG"
+const OVERLAP_POLICY_REJECT = "REJECT"
@@ -3,6 +3,7 @@ module timeTracker
 go 1.21.6
 
 require (
+	github.com/arran4/golang-ical v0.3.2
 	github.com/go-playground/validator/v10 v10.22.0
 	github.com/golang-migrate/migrate v3.5.4+incompatible
 	github.com/google/uuid v1.6.0
@@ -10,9 +11,10 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/lib/pq v1.10.9
 	github.com/rs/cors v1.11.0
-	github.com/sash20m/go-api-template v0.0.0-20240427135215-74ede58b45a3
+	github.com/shopspring/decimal v1.4.0
 	github.com/sirupsen/logrus v1.9.3
 	github.com/swaggo/http-swagger/v2 v2.0.2
+	github.com/teambition/rrule-go v1.8.2
 	github.com/unrolled/render v1.6.1
 	github.com/unrolled/secure v1.15.0
 	github.com/urfave/negroni v1.0.0
@@ -0,0 +1,314 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	ical "github.com/arran4/golang-ical"
+	"github.com/teambition/rrule-go"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"timeTracker/internal/model"
+)
+
+// MaxEvents limits the events read from one file, so that a rule like FREQ=MINUTELY over a wide
+// period can't flood the import.
+const MaxEvents = 1000
+
+// Events reads the events of the iCalendar file that start within the period of the request, in
+// the order of their start. With the UIDs of the request, only the events or the occurrences with
+// those UIDs are read. Recurring events are expanded into their occurrences, and modified
+// occurrences replace the ones of the rule. Cancelled events, all-day events and events without a
+// duration are left out. More than MaxEvents events are an error.
+func Events(r io.Reader, request model.ImportTracksRequest) ([]model.ImportEvent, error) {
+	location, err := time.LoadLocation(request.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := ical.ParseCalendar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Single occurrences of a recurring event are selected by their own UIDs.
+	selected := make(map[string]bool, len(request.UIDs))
+	partlySelected := make(map[string]bool)
+	for _, uid := range request.UIDs {
+		selected[uid] = true
+		if i := strings.LastIndex(uid, "/"); i >= 0 {
+			partlySelected[uid[:i]] = true
+		}
+	}
+
+	// Modified occurrences are events of their own with the UID of the recurring event.
+	modified := make(map[string]bool)
+	for _, event := range calendar.Events() {
+		recurrenceID := event.GetProperty(ical.ComponentPropertyRecurrenceId)
+		if recurrenceID == nil {
+			continue
+		}
+
+		times, _, err := propertyTimes(recurrenceID, location)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.Id(), err)
+		}
+
+		modified[occurrenceUID(event.Id(), times[0])] = true
+	}
+
+	var events []model.ImportEvent
+	for _, event := range calendar.Events() {
+		uid := event.Id()
+		if uid == "" {
+			return nil, errors.New("event without UID")
+		}
+
+		if len(selected) > 0 && !selected[uid] && !partlySelected[uid] {
+			continue
+		}
+
+		status := event.GetProperty(ical.ComponentPropertyStatus)
+		if status != nil && strings.EqualFold(status.Value, string(ical.ObjectStatusCancelled)) {
+			continue
+		}
+
+		start := event.GetProperty(ical.ComponentPropertyDtStart)
+		if start == nil {
+			return nil, fmt.Errorf("event %s: no DTSTART", uid)
+		}
+
+		times, allDay, err := propertyTimes(start, location)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", uid, err)
+		}
+
+		if allDay {
+			continue
+		}
+
+		startedAt := times[0]
+		duration, err := eventDuration(event, startedAt, location)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", uid, err)
+		}
+
+		if duration <= 0 {
+			continue
+		}
+
+		summary := ""
+		if property := event.GetProperty(ical.ComponentPropertySummary); property != nil {
+			summary = property.Value
+		}
+
+		occurrences := []time.Time{startedAt}
+		recurring := false
+		eventUID := uid
+		if recurrenceID := event.GetProperty(ical.ComponentPropertyRecurrenceId); recurrenceID != nil {
+			times, _, err := propertyTimes(recurrenceID, location)
+			if err != nil {
+				return nil, fmt.Errorf("event %s: %w", uid, err)
+			}
+
+			eventUID = occurrenceUID(uid, times[0])
+		} else if event.GetProperty(ical.ComponentPropertyRrule) != nil || event.GetProperty(ical.ComponentPropertyRdate) != nil {
+			recurring = true
+			occurrences, err = eventOccurrences(event, startedAt, request.From, request.To, location, MaxEvents-len(events))
+			if err != nil {
+				return nil, fmt.Errorf("event %s: %w", uid, err)
+			}
+		}
+
+		for _, occurrence := range occurrences {
+			if occurrence.Before(request.From) || !occurrence.Before(request.To) {
+				continue
+			}
+
+			occurrenceID := eventUID
+			if recurring {
+				occurrenceID = occurrenceUID(uid, occurrence)
+				if modified[occurrenceID] {
+					continue
+				}
+			}
+
+			if len(selected) > 0 && !selected[uid] && !selected[occurrenceID] {
+				continue
+			}
+
+			if len(events) == MaxEvents {
+				return nil, fmt.Errorf("more than %d events in the period", MaxEvents)
+			}
+
+			events = append(events, model.ImportEvent{
+				UID:       occurrenceID,
+				Summary:   summary,
+				StartedAt: occurrence.UTC(),
+				EndedAt:   occurrence.Add(duration).UTC(),
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartedAt.Before(events[j].StartedAt)
+	})
+
+	return events, nil
+}
+
+// eventOccurrences returns the starts of the occurrences of a recurring event within [from, to).
+// More than limit occurrences are an error.
+func eventOccurrences(event *ical.VEvent, startedAt time.Time, from time.Time, to time.Time, location *time.Location, limit int) ([]time.Time, error) {
+	set := rrule.Set{}
+	set.DTStart(startedAt)
+
+	if property := event.GetProperty(ical.ComponentPropertyRrule); property != nil {
+		option, err := rrule.StrToROptionInLocation(property.Value, startedAt.Location())
+		if err != nil {
+			return nil, err
+		}
+
+		option.Dtstart = startedAt
+		rule, err := rrule.NewRRule(*option)
+		if err != nil {
+			return nil, err
+		}
+
+		set.RRule(rule)
+	} else {
+		// Without a rule, the start is an occurrence besides the RDATEs.
+		set.RDate(startedAt)
+	}
+
+	for _, property := range event.GetProperties(ical.ComponentPropertyRdate) {
+		times, _, err := propertyTimes(property, location)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range times {
+			set.RDate(t)
+		}
+	}
+
+	for _, property := range event.GetProperties(ical.ComponentPropertyExdate) {
+		times, _, err := propertyTimes(property, location)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range times {
+			set.ExDate(t)
+		}
+	}
+
+	var occurrences []time.Time
+	next := set.Iterator()
+	for t, ok := next(); ok && t.Before(to); t, ok = next() {
+		if t.Before(from) {
+			continue
+		}
+
+		if len(occurrences) >= limit {
+			return nil, fmt.Errorf("more than %d events in the period", MaxEvents)
+		}
+
+		occurrences = append(occurrences, t)
+	}
+
+	return occurrences, nil
+}
+
+// eventDuration returns the duration of an event from its DTEND or its DURATION.
+func eventDuration(event *ical.VEvent, startedAt time.Time, location *time.Location) (time.Duration, error) {
+	if end := event.GetProperty(ical.ComponentPropertyDtEnd); end != nil {
+		times, _, err := propertyTimes(end, location)
+		if err != nil {
+			return 0, err
+		}
+
+		return times[0].Sub(startedAt), nil
+	}
+
+	if duration := event.GetProperty(ical.ComponentPropertyDuration); duration != nil {
+		return ParseDuration(duration.Value)
+	}
+
+	return 0, nil
+}
+
+// icalDuration matches a dur-value of RFC 5545, like "PT1H30M" or "P1W".
+var icalDuration = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseDuration parses a dur-value of RFC 5545. Days and weeks count as 24 hours each.
+func ParseDuration(value string) (time.Duration, error) {
+	match := icalDuration.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var duration time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if match[i+2] == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, err
+		}
+
+		duration += time.Duration(n) * unit
+	}
+
+	if match[1] == "-" {
+		duration = -duration
+	}
+
+	return duration, nil
+}
+
+// propertyTimes reads the comma separated date-times or dates of a property. Date-times without a
+// time zone are read in its TZID, or in location without one. allDay reports dates.
+func propertyTimes(property *ical.IANAProperty, location *time.Location) (times []time.Time, allDay bool, err error) {
+	if tzid, ok := property.ICalParameters[string(ical.ParameterTzid)]; ok && len(tzid) == 1 {
+		location, err = time.LoadLocation(tzid[0])
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	value, ok := property.ICalParameters[string(ical.ParameterValue)]
+	allDay = ok && len(value) == 1 && strings.EqualFold(value[0], string(ical.ValueDataTypeDate))
+
+	for _, field := range strings.Split(property.Value, ",") {
+		var t time.Time
+
+		switch {
+		case allDay || len(field) == len("20060102"):
+			allDay = true
+			t, err = time.ParseInLocation("20060102", field, location)
+		case strings.HasSuffix(field, "Z"):
+			t, err = time.Parse("20060102T150405Z", field)
+		default:
+			t, err = time.ParseInLocation("20060102T150405", field, location)
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		times = append(times, t)
+	}
+
+	return times, allDay, nil
+}
+
+// occurrenceUID identifies an occurrence of the recurring event uid by its start.
+func occurrenceUID(uid string, start time.Time) string {
+	return uid + "/" + start.UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,266 @@
+package calendar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"timeTracker/internal/model"
+)
+
+// ics returns an iCalendar file of the events, given as lines separated by newlines.
+func ics(events ...string) string {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//test//test//EN"}
+	for _, event := range events {
+		lines = append(lines, "BEGIN:VEVENT")
+		lines = append(lines, strings.Split(strings.TrimSpace(event), "\n")...)
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR", "")
+
+	return strings.Join(lines, "\r\n")
+}
+
+// event returns an event imported from start to end, given in UTC like "20240304T090000".
+func event(uid string, start string, end string) model.ImportEvent {
+	startedAt, _ := time.Parse("20060102T150405", start)
+	endedAt, _ := time.Parse("20060102T150405", end)
+
+	return model.ImportEvent{UID: uid, Summary: "Standup", StartedAt: startedAt, EndedAt: endedAt}
+}
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		timezone string
+		uids     []string
+		want     []model.ImportEvent
+	}{
+		{"single", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z`), "", nil,
+			[]model.ImportEvent{event("a", "20240304T090000", "20240304T091500")}},
+		{"DURATION", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DURATION:PT1H30M`), "", nil,
+			[]model.ImportEvent{event("a", "20240304T090000", "20240304T103000")}},
+		{"TZID", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART;TZID=Europe/Berlin:20240304T090000
+DTEND;TZID=Europe/Berlin:20240304T091500`), "", nil,
+			[]model.ImportEvent{event("a", "20240304T080000", "20240304T081500")}},
+		{"floating time in the time zone", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000
+DTEND:20240304T091500`), "America/New_York", nil,
+			[]model.ImportEvent{event("a", "20240304T140000", "20240304T141500")}},
+		{"all-day", ics(`
+UID:a
+SUMMARY:Holiday
+DTSTART;VALUE=DATE:20240304
+DTEND;VALUE=DATE:20240305`), "", nil, nil},
+		{"no duration", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z`), "", nil, nil},
+		{"cancelled", ics(`
+UID:a
+SUMMARY:Standup
+STATUS:CANCELLED
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z`), "", nil, nil},
+		{"outside the period", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240401T090000Z
+DTEND:20240401T091500Z`), "", nil, nil},
+		{"RRULE", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z
+RRULE:FREQ=DAILY;COUNT=3`), "", nil,
+			[]model.ImportEvent{
+				event("a/20240304T090000Z", "20240304T090000", "20240304T091500"),
+				event("a/20240305T090000Z", "20240305T090000", "20240305T091500"),
+				event("a/20240306T090000Z", "20240306T090000", "20240306T091500"),
+			}},
+		{"RRULE across DST in TZID", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART;TZID=Europe/Berlin:20240330T090000
+DTEND;TZID=Europe/Berlin:20240330T091500
+RRULE:FREQ=DAILY`), "", nil,
+			[]model.ImportEvent{
+				event("a/20240330T080000Z", "20240330T080000", "20240330T081500"),
+				event("a/20240331T070000Z", "20240331T070000", "20240331T071500"),
+			}},
+		{"RRULE from before the period", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240201T090000Z
+DURATION:PT15M
+RRULE:FREQ=MONTHLY`), "", nil,
+			[]model.ImportEvent{event("a/20240301T090000Z", "20240301T090000", "20240301T091500")}},
+		{"EXDATE", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z
+RRULE:FREQ=DAILY;COUNT=3
+EXDATE:20240305T090000Z`), "", nil,
+			[]model.ImportEvent{
+				event("a/20240304T090000Z", "20240304T090000", "20240304T091500"),
+				event("a/20240306T090000Z", "20240306T090000", "20240306T091500"),
+			}},
+		{"RDATE", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z
+RDATE:20240310T120000Z`), "", nil,
+			[]model.ImportEvent{
+				event("a/20240304T090000Z", "20240304T090000", "20240304T091500"),
+				event("a/20240310T120000Z", "20240310T120000", "20240310T121500"),
+			}},
+		{"RECURRENCE-ID", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z
+RRULE:FREQ=DAILY;COUNT=2`, `
+UID:a
+SUMMARY:Standup
+RECURRENCE-ID:20240305T090000Z
+DTSTART:20240305T140000Z
+DTEND:20240305T143000Z`), "", nil,
+			[]model.ImportEvent{
+				event("a/20240304T090000Z", "20240304T090000", "20240304T091500"),
+				event("a/20240305T090000Z", "20240305T140000", "20240305T143000"),
+			}},
+		{"selected UIDs", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z`, `
+UID:b
+SUMMARY:Standup
+DTSTART:20240304T080000Z
+DTEND:20240304T081500Z`), "", []string{"b"},
+			[]model.ImportEvent{event("b", "20240304T080000", "20240304T081500")}},
+		{"selected occurrences", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z
+RRULE:FREQ=DAILY;COUNT=3`, `
+UID:a
+SUMMARY:Standup
+RECURRENCE-ID:20240305T090000Z
+DTSTART:20240305T140000Z
+DTEND:20240305T143000Z`), "", []string{"a/20240305T090000Z", "a/20240306T090000Z"},
+			[]model.ImportEvent{
+				event("a/20240305T090000Z", "20240305T140000", "20240305T143000"),
+				event("a/20240306T090000Z", "20240306T090000", "20240306T091500"),
+			}},
+		{"in the order of their start", ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240304T090000Z
+DTEND:20240304T091500Z`, `
+UID:b
+SUMMARY:Standup
+DTSTART:20240304T080000Z
+DTEND:20240304T081500Z`), "", nil,
+			[]model.ImportEvent{
+				event("b", "20240304T080000", "20240304T081500"),
+				event("a", "20240304T090000", "20240304T091500"),
+			}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events, err := Events(strings.NewReader(test.file), model.ImportTracksRequest{
+				From:     time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+				UIDs:     test.uids,
+				Timezone: test.timezone,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(events, test.want) {
+				t.Errorf("Events() = %v, want %v", events, test.want)
+			}
+		})
+	}
+}
+
+func TestEventsLimit(t *testing.T) {
+	file := ics(`
+UID:a
+SUMMARY:Standup
+DTSTART:20240301T000000Z
+DURATION:PT1M
+RRULE:FREQ=MINUTELY`)
+
+	_, err := Events(strings.NewReader(file), model.ImportTracksRequest{
+		From: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err == nil {
+		t.Fatal("Events() of a minutely rule over a year succeeded, want an error")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		duration time.Duration
+		invalid  bool
+	}{
+		{"PT15M", 15 * time.Minute, false},
+		{"PT1H30M", 90 * time.Minute, false},
+		{"PT45S", 45 * time.Second, false},
+		{"P1D", 24 * time.Hour, false},
+		{"P1DT2H", 26 * time.Hour, false},
+		{"P1W", 7 * 24 * time.Hour, false},
+		{"-PT5M", -5 * time.Minute, false},
+		{"+PT5M", 5 * time.Minute, false},
+		{"P", 0, true},
+		{"PT", 0, true},
+		{"P1H", 0, true},
+		{"PT1.5H", 0, true},
+		{"", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			duration, err := ParseDuration(test.value)
+			if test.invalid {
+				if err == nil {
+					t.Errorf("ParseDuration() = %s, want an error", duration)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if duration != test.duration {
+				t.Errorf("ParseDuration() = %s, want %s", duration, test.duration)
+			}
+		})
+	}
+}
@@ -1,13 +1,43 @@
 package errors
 
+import "errors"
+
 // Err Used for custom errors
 type Err struct {
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
+	Kind    error  `json:"-"`
 }
 
 func (err Err) Error() string {
 	return err.Message
 }
 
+// Unwrap exposes the kind of the error, so it can be matched with errors.Is.
+func (err Err) Unwrap() error {
+	return err.Kind
+}
+
 const ErrResourceUnavailable = "This resource is unavailable"
+
+// Kinds of errors returned by the storage layer. Handlers use them to pick the status code.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+	ErrInvalid  = errors.New("invalid")
+)
+
+// NotFound creates an Err for a missing record.
+func NotFound(message string) Err {
+	return Err{Message: message, Kind: ErrNotFound}
+}
+
+// Conflict creates an Err for a change that clashes with the current state of the data.
+func Conflict(message string, data any) Err {
+	return Err{Message: message, Data: data, Kind: ErrConflict}
+}
+
+// Invalid creates an Err for a change that is rejected by a business rule.
+func Invalid(message string) Err {
+	return Err{Message: message, Kind: ErrInvalid}
+}
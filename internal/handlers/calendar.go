@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	ical "github.com/arran4/golang-ical"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"timeTracker/internal/calendar"
+	"timeTracker/internal/model"
+)
+
+// calendarUIDDomain makes the UIDs of the sessions in the calendar feed globally unique.
+const calendarUIDDomain = "@time-tracker"
+
+// maxCalendarSize limits the size of an uploaded iCalendar file.
+const maxCalendarSize = 10 << 20
+
+// ResetCalendarToken godoc
+// @Summary		Issue a new token of the calendar feed of a user
+// @Tags			Users
+// @Produce		json
+// @Accept			json
+// @Param	request	body		model.CalendarTokenRequest	true	"user id"
+// @Success		200	{object} model.CalendarToken
+// @Router			/api/users/calendar-token [post]
+func (h *Handlers) ResetCalendarToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var request model.CalendarTokenRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(request)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	token, err := h.Storage.ResetCalendarToken(ctx, request.ID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, model.CalendarToken{Token: token, Feed: "/api/calendar/" + token + ".ics"})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetCalendar godoc
+// @Summary		Get the tracked sessions of a user as an iCalendar feed
+// @Tags			Users
+// @Produce		text/calendar
+// @Param token path string true "token of the calendar feed"
+// @Param from query string false "period start, RFC 3339"
+// @Param to query string false "period end, RFC 3339"
+// @Success		200	{string} string "iCalendar feed"
+// @Router			/api/calendar/{token}.ics [get]
+func (h *Handlers) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var filter model.CalendarFilter
+	var err error
+
+	if r.URL.Query().Get("from") != "" {
+		filter.From, err = time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.URL.Query().Get("to") != "" {
+		filter.To, err = time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	user, err := h.Storage.GetCalendarUser(ctx, mux.Vars(r)["token"])
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	feed := ical.NewCalendar()
+	feed.SetProductId("-//timeTracker//Time Tracker//EN")
+	feed.SetMethod(ical.MethodPublish)
+	feed.SetCalscale("GREGORIAN")
+	feed.SetXWRCalName(strings.TrimSpace(user.Name + " " + user.Surname + " tracked time"))
+
+	now := time.Now()
+	err = h.Storage.ExportCalendar(ctx, user.ID, filter, func(row model.SessionRow) error {
+		event := feed.AddEvent(row.ID.String() + calendarUIDDomain)
+		event.SetDtStampTime(now)
+		event.SetStartAt(row.StartedAt)
+		event.SetEndAt(row.EndedAt)
+		event.SetSummary(row.TaskName)
+		if row.Note != "" {
+			event.SetDescription(row.Note)
+		}
+
+		return nil
+	})
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", "inline; filename=\"time-tracker.ics\"")
+	w.WriteHeader(http.StatusOK)
+
+	err = feed.SerializeTo(w, ical.WithNewLineWindows)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ImportTracks godoc
+// @Summary		Import the events of an iCalendar file as time entries
+// @Description	Each event lists the sessions it overlaps. With the REJECT overlap policy overlapping events are skipped with the status overlaps, so that the preview shows which events to leave out of uid
+// @Tags			Track
+// @Produce		json
+// @Accept			multipart/form-data
+// @Param file formData file true "iCalendar file"
+// @Param task_id formData string true "task of the time entries"
+// @Param from formData string true "start of the period of the events, RFC 3339"
+// @Param to formData string true "end of the period of the events, RFC 3339"
+// @Param uid formData []string false "UIDs of the events or occurrences to import, all by default" collectionFormat(multi)
+// @Param timezone formData string false "IANA time zone of the times without one, UTC by default"
+// @Param preview formData bool false "only show what would be imported"
+// @Success		200	{object} model.ImportResult
+// @Router			/api/tracks/import [post]
+func (h *Handlers) ImportTracks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var request model.ImportTracksRequest
+
+	err := r.ParseMultipartForm(maxCalendarSize)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if r.FormValue("task_id") != "" {
+		request.TaskID, err = uuid.Parse(r.FormValue("task_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.FormValue("from") != "" {
+		request.From, err = time.Parse(time.RFC3339, r.FormValue("from"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.FormValue("to") != "" {
+		request.To, err = time.Parse(time.RFC3339, r.FormValue("to"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.FormValue("preview") != "" {
+		request.Preview, err = strconv.ParseBool(r.FormValue("preview"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	request.UIDs = r.MultipartForm.Value["uid"]
+	request.Timezone = r.FormValue("timezone")
+
+	err = Validate.Struct(request)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	events, err := calendar.Events(file, request)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.Storage.ImportTracks(ctx, request, events)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, result)
+	if err != nil {
+		panic(err)
+	}
+}
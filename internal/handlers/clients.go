@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+// GetClients godoc
+// @Summary		Get all clients
+// @Tags			Clients
+// @Produce		json
+// @Success		200	{object} []model.Client
+// @Param limit query string false "pagination limit"
+// @Param page query string false "pagination page"
+// @Param sort query string false "pagination sort"
+// @Param name query string false "filter name"
+// @Router			/api/clients [get]
+func (h *Handlers) GetClients(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filters model.ClientFilter
+	var pagination utils.Pagination
+
+	filters.NameFilter = r.URL.Query().Get("name")
+
+	if r.URL.Query().Get("limit") != "" {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Limit = limit
+	}
+
+	if r.URL.Query().Get("page") != "" {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Page = page
+	}
+
+	pagination.Sort = r.URL.Query().Get("sort")
+
+	clients, err := h.Storage.GetClients(ctx, filters, pagination)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, clients)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetClient godoc
+// @Summary		Get a specific client
+// @Tags			Clients
+// @Produce		json
+// @Param	id	path		string	true	"client id"
+// @Success		200	{object} model.Client
+// @Router			/api/clients/{id} [get]
+func (h *Handlers) GetClient(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clientID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	client, err := h.Storage.GetClient(ctx, clientID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, client)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddClient godoc
+// @Summary		Add a client
+// @Tags			Clients
+// @Produce		json
+// @Accept			json
+// @Param	client request	body		model.AddClientRequest	true	"client request"
+// @Success		200	{object} model.Client
+// @Router			/api/clients [post]
+func (h *Handlers) AddClient(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var client model.AddClientRequest
+
+	err := json.NewDecoder(r.Body).Decode(&client)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(client)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	clientEntity := model.Client{
+		Name:        client.Name,
+		Contact:     client.Contact,
+		Currency:    client.Currency,
+		DefaultRate: client.DefaultRate,
+		Base:        model.Base{ID: uuid.New()},
+	}
+
+	clientResponse, err := h.Storage.AddClient(ctx, clientEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, clientResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateClient godoc
+// @Summary		Update a specific client
+// @Tags			Clients
+// @Produce		json
+// @Accept			json
+// @Param	client request	body		model.UpdateClientRequest	true	"client request"
+// @Success		200	{object} model.Client
+// @Router			/api/clients [put]
+func (h *Handlers) UpdateClient(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var client model.UpdateClientRequest
+
+	err := json.NewDecoder(r.Body).Decode(&client)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(client)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	clientEntity := model.Client{
+		Name:        client.Name,
+		Contact:     client.Contact,
+		Currency:    client.Currency,
+		DefaultRate: client.DefaultRate,
+		Base:        model.Base{ID: client.ID},
+	}
+
+	clientResponse, err := h.Storage.UpdateClient(ctx, clientEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, clientResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeleteClient godoc
+// @Summary		Delete a specific client, keeping its projects without a client
+// @Tags			Clients
+// @Produce		json
+// @Accept			json
+// @Param	client request	body		model.DeleteClientRequest	true	"client request"
+// @Success		200	{object} bool
+// @Router			/api/clients [delete]
+func (h *Handlers) DeleteClient(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var client model.DeleteClientRequest
+
+	err := json.NewDecoder(r.Body).Decode(&client)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(client)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	ok, err := h.Storage.DeleteClient(ctx, client.ID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, ok)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetClientReport godoc
+// @Summary		Calculate the time and the billable amount of clients for a period
+// @Description	The time and the billable time are both cut to the period as in calc-time, so the billable time is a part of the time
+// @Tags			Clients
+// @Produce		json
+// @Accept			json
+// @Param	request	body		model.ClientReportRequest	true	"client id and period"
+// @Success		200	{object} []model.ClientTotal
+// @Router			/api/clients/report [post]
+func (h *Handlers) GetClientReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var request model.ClientReportRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.Storage.GetClientReport(ctx, request)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, report)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"timeTracker/internal/model"
+	"timeTracker/pkg/logger"
+)
+
+// sessionsHeader is the header of the csv exports of sessions.
+var sessionsHeader = []string{"name", "surname", "task", "started_at", "ended_at", "duration_seconds", "duration_hours", "note"}
+
+// csvCell returns the text entered by a user as a csv cell. Text that a spreadsheet would read as
+// a formula is prefixed with a quote so it is shown as text.
+func csvCell(text string) string {
+	if strings.IndexAny(text, "=+-@\t\r") == 0 {
+		return "'" + text
+	}
+
+	return text
+}
+
+// csvRequested reports whether the client asked for csv with the format query parameter, or with
+// the Accept header when the parameter is not set.
+func csvRequested(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("format") {
+	case "csv":
+		return true, nil
+	case "json":
+		return false, nil
+	case "":
+	default:
+		return false, errors.New("format must be json or csv")
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == "text/csv" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// sendSessionsCSV streams the sessions passed by export to its write as a csv attachment. An error
+// before the first session is sent as usual; after it the response has begun, so the error is
+// logged and the file is cut short.
+func (h *Handlers) sendSessionsCSV(w http.ResponseWriter, filename string, export func(write func(model.SessionRow) error) error) {
+	var writer *csv.Writer
+
+	start := func() error {
+		writer = h.Sender.CSV(w, http.StatusOK, filename)
+		return writer.Write(sessionsHeader)
+	}
+
+	err := export(func(row model.SessionRow) error {
+		if writer == nil {
+			err := start()
+			if err != nil {
+				return err
+			}
+		}
+
+		seconds := int64(row.Time.Round(time.Second) / time.Second)
+
+		return writer.Write([]string{
+			csvCell(row.UserName),
+			csvCell(row.UserSurname),
+			csvCell(row.TaskName),
+			row.StartedAt.UTC().Format(time.RFC3339),
+			row.EndedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(seconds, 10),
+			decimal.NewFromInt(seconds).Div(decimal.NewFromInt(3600)).StringFixed(2),
+			csvCell(row.Note),
+		})
+	})
+
+	if writer == nil {
+		if err != nil {
+			h.sendError(w, err)
+			return
+		}
+
+		err = start()
+	}
+
+	if err == nil {
+		writer.Flush()
+		err = writer.Error()
+	}
+
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"err": err.Error(),
+		}).Error("Can't stream the csv export " + filename)
+	}
+}
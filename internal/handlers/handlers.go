@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/go-playground/validator/v10"
+	"net/http"
 	"timeTracker/config"
+	localErr "timeTracker/internal/errors"
 	"timeTracker/internal/storage"
 	"timeTracker/pkg/httputils"
 )
@@ -16,3 +19,25 @@ type Handlers struct {
 
 // Validate is a singleton that provides validation services for in handlers.
 var Validate *validator.Validate = validator.New(validator.WithRequiredStructEnabled())
+
+// sendError sends an error returned by the storage with the status code that matches its kind.
+func (h *Handlers) sendError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, localErr.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, localErr.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, localErr.ErrInvalid):
+		status = http.StatusBadRequest
+	}
+
+	var errInfo localErr.Err
+	if errors.As(err, &errInfo) {
+		h.Sender.JSON(w, status, errInfo)
+		return
+	}
+
+	h.Sender.JSON(w, status, err.Error())
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+// GetInvoices godoc
+// @Summary		Get all invoices
+// @Tags			Invoices
+// @Produce		json
+// @Success		200	{object} []model.Invoice
+// @Param limit query string false "pagination limit"
+// @Param page query string false "pagination page"
+// @Param sort query string false "pagination sort"
+// @Param client_id query string false "filter client id"
+// @Param status query string false "filter status"
+// @Router			/api/invoices [get]
+func (h *Handlers) GetInvoices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filters model.InvoiceFilter
+	var pagination utils.Pagination
+
+	filters.StatusFilter = r.URL.Query().Get("status")
+
+	if r.URL.Query().Get("client_id") != "" {
+		clientID, err := uuid.Parse(r.URL.Query().Get("client_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.ClientIDFilter = clientID
+	}
+
+	if r.URL.Query().Get("limit") != "" {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Limit = limit
+	}
+
+	if r.URL.Query().Get("page") != "" {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Page = page
+	}
+
+	pagination.Sort = r.URL.Query().Get("sort")
+	if pagination.Sort == "" {
+		pagination.Sort = "number desc"
+	}
+
+	invoices, err := h.Storage.GetInvoices(ctx, filters, pagination)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, invoices)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetInvoice godoc
+// @Summary		Get a specific invoice with its lines, as JSON or as a printable HTML document
+// @Tags			Invoices
+// @Produce		json,html
+// @Param	id	path		string	true	"invoice id"
+// @Param format query string false "json (the default) or html"
+// @Success		200	{object} model.Invoice
+// @Router			/api/invoices/{id} [get]
+func (h *Handlers) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	invoiceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "html" {
+		h.Sender.JSON(w, http.StatusBadRequest, "format must be json or html")
+		return
+	}
+
+	invoice, err := h.Storage.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	if format == "html" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"invoice-%06d.html\"", invoice.Number))
+		err = h.Sender.HTML(w, http.StatusOK, "invoice", invoice)
+	} else {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"invoice-%06d.json\"", invoice.Number))
+		err = h.Sender.JSON(w, http.StatusOK, invoice)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddInvoice godoc
+// @Summary		Invoice the unbilled billable time of a client for a period
+// @Tags			Invoices
+// @Produce		json
+// @Accept			json
+// @Param	invoice request	body		model.AddInvoiceRequest	true	"invoice request"
+// @Success		200	{object} model.Invoice
+// @Router			/api/invoices [post]
+func (h *Handlers) AddInvoice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var invoice model.AddInvoiceRequest
+
+	err := json.NewDecoder(r.Body).Decode(&invoice)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(invoice)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	invoiceResponse, err := h.Storage.AddInvoice(ctx, invoice)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, invoiceResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateInvoiceStatus godoc
+// @Summary		Issue, pay or void a specific invoice
+// @Tags			Invoices
+// @Produce		json
+// @Accept			json
+// @Param	invoice request	body		model.UpdateInvoiceStatusRequest	true	"invoice status request"
+// @Success		200	{object} model.Invoice
+// @Router			/api/invoices/status [put]
+func (h *Handlers) UpdateInvoiceStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var invoice model.UpdateInvoiceStatusRequest
+
+	err := json.NewDecoder(r.Body).Decode(&invoice)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(invoice)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	invoiceResponse, err := h.Storage.UpdateInvoiceStatus(ctx, invoice.ID, invoice.Status)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, invoiceResponse)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+// GetProjects godoc
+// @Summary		Get all projects
+// @Tags			Projects
+// @Produce		json
+// @Success		200	{object} []model.Project
+// @Param limit query string false "pagination limit"
+// @Param page query string false "pagination page"
+// @Param sort query string false "pagination sort"
+// @Param name query string false "filter name"
+// @Param client_id query string false "filter client id"
+// @Router			/api/projects [get]
+func (h *Handlers) GetProjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filters model.ProjectFilter
+	var pagination utils.Pagination
+
+	filters.NameFilter = r.URL.Query().Get("name")
+
+	if r.URL.Query().Get("client_id") != "" {
+		clientID, err := uuid.Parse(r.URL.Query().Get("client_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.ClientIDFilter = clientID
+	}
+
+	if r.URL.Query().Get("limit") != "" {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Limit = limit
+	}
+
+	if r.URL.Query().Get("page") != "" {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Page = page
+	}
+
+	pagination.Sort = r.URL.Query().Get("sort")
+
+	projects, err := h.Storage.GetProjects(ctx, filters, pagination)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, projects)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetProject godoc
+// @Summary		Get a specific project
+// @Tags			Projects
+// @Produce		json
+// @Param	id	path		string	true	"project id"
+// @Success		200	{object} model.Project
+// @Router			/api/projects/{id} [get]
+func (h *Handlers) GetProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	project, err := h.Storage.GetProject(ctx, projectID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, project)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddProject godoc
+// @Summary		Add a project
+// @Tags			Projects
+// @Produce		json
+// @Accept			json
+// @Param	project request	body		model.AddProjectRequest	true	"project request"
+// @Success		200	{object} model.Project
+// @Router			/api/projects [post]
+func (h *Handlers) AddProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var project model.AddProjectRequest
+
+	err := json.NewDecoder(r.Body).Decode(&project)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(project)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	projectEntity := model.Project{
+		Name:        project.Name,
+		Description: project.Description,
+		ClientID:    project.ClientID,
+		Base:        model.Base{ID: uuid.New()},
+	}
+
+	projectResponse, err := h.Storage.AddProject(ctx, projectEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, projectResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateProject godoc
+// @Summary		Update a specific project
+// @Tags			Projects
+// @Produce		json
+// @Accept			json
+// @Param	project request	body		model.UpdateProjectRequest	true	"project request"
+// @Success		200	{object} model.Project
+// @Router			/api/projects [put]
+func (h *Handlers) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var project model.UpdateProjectRequest
+
+	err := json.NewDecoder(r.Body).Decode(&project)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(project)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	projectEntity := model.Project{
+		Name:        project.Name,
+		Description: project.Description,
+		ClientID:    project.ClientID,
+		Base:        model.Base{ID: project.ID},
+	}
+
+	projectResponse, err := h.Storage.UpdateProject(ctx, projectEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, projectResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeleteProject godoc
+// @Summary		Delete a specific project, keeping its tasks without a project
+// @Tags			Projects
+// @Produce		json
+// @Accept			json
+// @Param	project request	body		model.DeleteProjectRequest	true	"project request"
+// @Success		200	{object} bool
+// @Router			/api/projects [delete]
+func (h *Handlers) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var project model.DeleteProjectRequest
+
+	err := json.NewDecoder(r.Body).Decode(&project)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(project)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	ok, err := h.Storage.DeleteProject(ctx, project.ID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, ok)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"timeTracker/internal/model"
+)
+
+// GetRates godoc
+// @Summary		Get the rates of a user, a project or a task
+// @Tags			Rates
+// @Produce		json
+// @Success		200	{object} []model.Rate
+// @Param user_id query string false "filter user id"
+// @Param project_id query string false "filter project id"
+// @Param task_id query string false "filter task id"
+// @Router			/api/rates [get]
+func (h *Handlers) GetRates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var filters model.RateFilter
+
+	if r.URL.Query().Get("user_id") != "" {
+		userID, err := uuid.Parse(r.URL.Query().Get("user_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.UserIDFilter = userID
+	}
+
+	if r.URL.Query().Get("project_id") != "" {
+		projectID, err := uuid.Parse(r.URL.Query().Get("project_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.ProjectIDFilter = projectID
+	}
+
+	if r.URL.Query().Get("task_id") != "" {
+		taskID, err := uuid.Parse(r.URL.Query().Get("task_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.TaskIDFilter = taskID
+	}
+
+	rates, err := h.Storage.GetRates(ctx, filters)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, rates)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddRate godoc
+// @Summary		Set a new hourly rate for a user, a project or a task
+// @Tags			Rates
+// @Produce		json
+// @Accept			json
+// @Param	rate request	body		model.AddRateRequest	true	"rate request"
+// @Success		200	{object} model.Rate
+// @Router			/api/rates [post]
+func (h *Handlers) AddRate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var rate model.AddRateRequest
+
+	err := json.NewDecoder(r.Body).Decode(&rate)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(rate)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	rateEntity := model.Rate{
+		UserID:    rate.UserID,
+		ProjectID: rate.ProjectID,
+		TaskID:    rate.TaskID,
+		Amount:    rate.Amount,
+		Base:      model.Base{ID: uuid.New()},
+	}
+
+	if rate.StartsAt != nil {
+		rateEntity.StartsAt = *rate.StartsAt
+	}
+
+	rateResponse, err := h.Storage.AddRate(ctx, rateEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, rateResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetEarnings godoc
+// @Summary		Calculate the earnings of the billable time
+// @Tags			Rates
+// @Produce		json
+// @Accept			json
+// @Param	request	body		model.EarningsRequest	true	"user id or project id and period"
+// @Success		200	{object} model.EarningsReport
+// @Router			/api/earnings [post]
+func (h *Handlers) GetEarnings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var request model.EarningsRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(request)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	report, err := h.Storage.GetEarnings(ctx, request)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, report)
+	if err != nil {
+		panic(err)
+	}
+}
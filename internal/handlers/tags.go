@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"strings"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+// GetTags godoc
+// @Summary		Get all tags
+// @Tags			Tags
+// @Produce		json
+// @Success		200	{object} []model.Tag
+// @Param limit query string false "pagination limit"
+// @Param page query string false "pagination page"
+// @Param sort query string false "pagination sort"
+// @Router			/api/tags [get]
+func (h *Handlers) GetTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var pagination utils.Pagination
+
+	if r.URL.Query().Get("limit") != "" {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Limit = limit
+	}
+
+	if r.URL.Query().Get("page") != "" {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Page = page
+	}
+
+	pagination.Sort = r.URL.Query().Get("sort")
+
+	tags, err := h.Storage.GetTags(ctx, pagination)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, tags)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddTag godoc
+// @Summary		Add a tag
+// @Tags			Tags
+// @Produce		json
+// @Accept			json
+// @Param	tag request	body		model.AddTagRequest	true	"tag request"
+// @Success		200	{object} model.Tag
+// @Router			/api/tags [post]
+func (h *Handlers) AddTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var tag model.AddTagRequest
+
+	err := json.NewDecoder(r.Body).Decode(&tag)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(tag)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	tagEntity := model.Tag{
+		Name: tag.Name,
+		Base: model.Base{ID: uuid.New()},
+	}
+
+	tagResponse, err := h.Storage.AddTag(ctx, tagEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, tagResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateTag godoc
+// @Summary		Rename a specific tag
+// @Tags			Tags
+// @Produce		json
+// @Accept			json
+// @Param	tag request	body		model.UpdateTagRequest	true	"tag request"
+// @Success		200	{object} model.Tag
+// @Router			/api/tags [put]
+func (h *Handlers) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var tag model.UpdateTagRequest
+
+	err := json.NewDecoder(r.Body).Decode(&tag)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(tag)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	tagEntity := model.Tag{
+		Name: tag.Name,
+		Base: model.Base{ID: tag.ID},
+	}
+
+	tagResponse, err := h.Storage.UpdateTag(ctx, tagEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, tagResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeleteTag godoc
+// @Summary		Delete a specific tag, removing it from tasks and sessions
+// @Tags			Tags
+// @Produce		json
+// @Accept			json
+// @Param	tag request	body		model.DeleteTagRequest	true	"tag request"
+// @Success		200	{object} bool
+// @Router			/api/tags [delete]
+func (h *Handlers) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var tag model.DeleteTagRequest
+
+	err := json.NewDecoder(r.Body).Decode(&tag)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(tag)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	ok, err := h.Storage.DeleteTag(ctx, tag.ID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, ok)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetTaskTags godoc
+// @Summary		Replace the tags of a task
+// @Tags			Tags
+// @Produce		json
+// @Accept			json
+// @Param	tags request	body		model.SetTagsRequest	true	"task id and tag ids"
+// @Success		200	{object} model.Task
+// @Router			/api/tasks/tags [put]
+func (h *Handlers) SetTaskTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var tags model.SetTagsRequest
+
+	err := json.NewDecoder(r.Body).Decode(&tags)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(tags)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	taskResponse, err := h.Storage.SetTaskTags(ctx, tags.ID, tags.TagIDs)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, taskResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetTrackTags godoc
+// @Summary		Replace the tags of a session
+// @Tags			Tags
+// @Produce		json
+// @Accept			json
+// @Param	tags request	body		model.SetTagsRequest	true	"session id and tag ids"
+// @Success		200	{object} model.TaskTrack
+// @Router			/api/tracks/tags [put]
+func (h *Handlers) SetTrackTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var tags model.SetTagsRequest
+
+	err := json.NewDecoder(r.Body).Decode(&tags)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(tags)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	trackResponse, err := h.Storage.SetTrackTags(ctx, tags.ID, tags.TagIDs)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackResponse)
+	if err != nil {
+		panic(err)
+	}
+}
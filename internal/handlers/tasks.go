@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+// GetTasks godoc
+// @Summary		Get all tasks
+// @Tags			Tasks
+// @Produce		json
+// @Success		200	{object} []model.Task
+// @Param limit query string false "pagination limit"
+// @Param page query string false "pagination page"
+// @Param sort query string false "pagination sort"
+// @Param user_id query string false "filter user id"
+// @Param project_id query string false "filter project id"
+// @Param parent_id query string false "filter parent task id"
+// @Param name query string false "filter name"
+// @Param status query string false "filter status"
+// @Router			/api/tasks [get]
+func (h *Handlers) GetTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filters model.TaskFilter
+	var pagination utils.Pagination
+
+	filters.NameFilter = r.URL.Query().Get("name")
+	filters.StatusFilter = r.URL.Query().Get("status")
+
+	if r.URL.Query().Get("user_id") != "" {
+		userID, err := uuid.Parse(r.URL.Query().Get("user_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.UserIDFilter = userID
+	}
+
+	if r.URL.Query().Get("project_id") != "" {
+		projectID, err := uuid.Parse(r.URL.Query().Get("project_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.ProjectIDFilter = projectID
+	}
+
+	if r.URL.Query().Get("parent_id") != "" {
+		parentID, err := uuid.Parse(r.URL.Query().Get("parent_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.ParentIDFilter = parentID
+	}
+
+	if r.URL.Query().Get("limit") != "" {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Limit = limit
+	}
+
+	if r.URL.Query().Get("page") != "" {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Page = page
+	}
+
+	pagination.Sort = r.URL.Query().Get("sort")
+
+	tasks, err := h.Storage.GetTasks(ctx, filters, pagination)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, tasks)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetTask godoc
+// @Summary		Get a specific task
+// @Tags			Tasks
+// @Produce		json
+// @Param	id	path		string	true	"task id"
+// @Success		200	{object} model.Task
+// @Router			/api/tasks/{id} [get]
+func (h *Handlers) GetTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := h.Storage.GetTask(ctx, taskID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, task)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddTask godoc
+// @Summary		Add a task for a user
+// @Tags			Tasks
+// @Produce		json
+// @Accept			json
+// @Param	task request	body		model.AddTaskRequest	true	"task request"
+// @Success		200	{object} model.Task
+// @Router			/api/tasks [post]
+func (h *Handlers) AddTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var task model.AddTaskRequest
+
+	err := json.NewDecoder(r.Body).Decode(&task)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(task)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	estimate, err := parseEstimate(task.Estimate)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	taskEntity := model.Task{
+		Name:      task.Name,
+		UserID:    task.UserID,
+		ProjectID: task.ProjectID,
+		ParentID:  task.ParentID,
+		Estimate:  estimate,
+		Billable:  task.Billable,
+		Base:      model.Base{ID: uuid.New()},
+	}
+
+	taskResponse, err := h.Storage.AddTask(ctx, taskEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, taskResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateTask godoc
+// @Summary		Change the name, project, parent task, estimate or billable flag of a specific task
+// @Description	Only the fields in the request change; the nil uuid removes the project or the parent task, an empty estimate removes the estimate
+// @Tags			Tasks
+// @Produce		json
+// @Accept			json
+// @Param	task request	body		model.UpdateTaskRequest	true	"task request"
+// @Success		200	{object} model.Task
+// @Router			/api/tasks [put]
+func (h *Handlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var task model.UpdateTaskRequest
+
+	err := json.NewDecoder(r.Body).Decode(&task)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(task)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	update := model.TaskUpdate{
+		Name:      task.Name,
+		ProjectID: task.ProjectID,
+		ParentID:  task.ParentID,
+		Billable:  task.Billable,
+	}
+
+	if task.Estimate != nil {
+		estimate, err := parseEstimate(*task.Estimate)
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		update.Estimate = new(time.Duration)
+		if estimate != nil {
+			update.Estimate = estimate
+		}
+	}
+
+	taskResponse, err := h.Storage.UpdateTask(ctx, task.ID, update)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, taskResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateTaskStatus godoc
+// @Summary		Move a specific task to another status
+// @Tags			Tasks
+// @Produce		json
+// @Accept			json
+// @Param	task request	body		model.UpdateTaskStatusRequest	true	"task status request"
+// @Success		200	{object} model.Task
+// @Router			/api/tasks/status [put]
+func (h *Handlers) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var task model.UpdateTaskStatusRequest
+
+	err := json.NewDecoder(r.Body).Decode(&task)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(task)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	taskResponse, err := h.Storage.UpdateTaskStatus(ctx, task.ID, task.Status)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, taskResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeleteTask godoc
+// @Summary		Delete a specific task, moving its subtasks to its parent
+// @Tags			Tasks
+// @Produce		json
+// @Accept			json
+// @Param	task request	body		model.DeleteTaskRequest	true	"task request"
+// @Success		200	{object} bool
+// @Router			/api/tasks [delete]
+func (h *Handlers) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var task model.DeleteTaskRequest
+
+	err := json.NewDecoder(r.Body).Decode(&task)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(task)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	ok, err := h.Storage.DeleteTask(ctx, task.ID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, ok)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetEstimates godoc
+// @Summary		Compare the estimates of tasks with the tracked time
+// @Tags			Tasks
+// @Produce		json
+// @Success		200	{object} model.EstimateReport
+// @Param user_id query string false "user id, required without project id"
+// @Param project_id query string false "project id"
+// @Router			/api/estimates [get]
+func (h *Handlers) GetEstimates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var filters model.EstimateFilter
+
+	if r.URL.Query().Get("user_id") != "" {
+		userID, err := uuid.Parse(r.URL.Query().Get("user_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.UserIDFilter = userID
+	}
+
+	if r.URL.Query().Get("project_id") != "" {
+		projectID, err := uuid.Parse(r.URL.Query().Get("project_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.ProjectIDFilter = projectID
+	}
+
+	if filters.UserIDFilter == uuid.Nil && filters.ProjectIDFilter == uuid.Nil {
+		h.Sender.JSON(w, http.StatusBadRequest, "user_id or project_id is required")
+		return
+	}
+
+	report, err := h.Storage.GetEstimates(ctx, filters)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, report)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// parseEstimate parses the estimate of a task request. An empty estimate is no estimate.
+func parseEstimate(value string) (*time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	estimate, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if estimate <= 0 {
+		return nil, errors.New("estimate must be positive")
+	}
+
+	return &estimate, nil
+}
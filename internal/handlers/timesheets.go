@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"time"
+	"timeTracker/internal/model"
+)
+
+// GetTimesheet godoc
+// @Summary		Get the time spent per user, task and day of a week
+// @Tags			Track
+// @Produce		json
+// @Param week query string true "ISO week, like 2024-W12"
+// @Param user_id query string false "user id, required without project_id"
+// @Param project_id query string false "project id, required without user_id"
+// @Param timezone query string false "IANA timezone of the days, UTC by default"
+// @Param duration_format query string false "primary duration format: hh_mm (the default), seconds or iso8601"
+// @Success		200	{object} model.TimesheetResult
+// @Router			/api/timesheet [get]
+func (h *Handlers) GetTimesheet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var request model.TimesheetRequest
+
+	format, err := durationFormat(r)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	request.Week = r.URL.Query().Get("week")
+	request.Timezone = r.URL.Query().Get("timezone")
+
+	if r.URL.Query().Get("user_id") != "" {
+		request.UserID, err = uuid.Parse(r.URL.Query().Get("user_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.URL.Query().Get("project_id") != "" {
+		request.ProjectID, err = uuid.Parse(r.URL.Query().Get("project_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	err = Validate.Struct(request)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	timesheet, err := h.Storage.GetTimesheet(ctx, request)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, timesheetResult(timesheet, format))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateTimesheet godoc
+// @Summary		Set the time spent by a user per task and day of a week
+// @Description	Time is added as manual sessions and removed from the manual sessions of the day; an empty day is left as it is
+// @Tags			Track
+// @Produce		json
+// @Accept			json
+// @Param	request	body		model.UpdateTimesheetRequest	true	"user id, week and the time of each task per day, like 1h30m"
+// @Param duration_format query string false "primary duration format: hh_mm (the default), seconds or iso8601"
+// @Success		200	{object} model.TimesheetResult
+// @Router			/api/timesheet [put]
+func (h *Handlers) UpdateTimesheet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var request model.UpdateTimesheetRequest
+
+	format, err := durationFormat(r)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(request)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	var cells []model.TimesheetCell
+	for _, row := range request.Rows {
+		for day, value := range row.Days {
+			if value == "" {
+				continue
+			}
+
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			if d < 0 || d > 24*time.Hour {
+				h.Sender.JSON(w, http.StatusBadRequest, fmt.Sprintf("time %q must be between 0 and 24h", value))
+				return
+			}
+
+			cells = append(cells, model.TimesheetCell{TaskID: row.TaskID, Day: day, Time: d})
+		}
+	}
+
+	timesheet, err := h.Storage.UpdateTimesheet(ctx, model.TimesheetRequest{UserID: request.UserID, Week: request.Week, Timezone: request.Timezone}, cells)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, timesheetResult(timesheet, format))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// timesheetResult formats the durations of the timesheet.
+func timesheetResult(timesheet model.Timesheet, format string) model.TimesheetResult {
+	result := model.TimesheetResult{
+		Week:  timesheet.Week,
+		Days:  timesheet.Days,
+		Rows:  make([]model.TimesheetRowResult, 0, len(timesheet.Rows)),
+		Total: model.FormatDuration(timesheet.Total, format),
+	}
+
+	for day, d := range timesheet.DayTotals {
+		result.DayTotals[day] = model.FormatDuration(d, format)
+	}
+
+	for _, row := range timesheet.Rows {
+		rowResult := model.TimesheetRowResult{
+			UserID:      row.UserID,
+			UserName:    row.UserName,
+			UserSurname: row.UserSurname,
+			TaskID:      row.TaskID,
+			TaskName:    row.TaskName,
+			Total:       model.FormatDuration(row.Total, format),
+		}
+
+		for day, d := range row.Days {
+			rowResult.Days[day] = model.FormatDuration(d, format)
+		}
+
+		result.Rows = append(result.Rows, rowResult)
+	}
+
+	return result
+}
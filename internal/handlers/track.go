@@ -2,14 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"net/http"
-	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
 )
 
 // StartTrackTask godoc
@@ -17,7 +19,7 @@ import (
 // @Tags		Track
 // @Produce		json
 // @Accept		json
-// @Param	id	body		model.TaskTrackRequest	true	"task id"
+// @Param	id	body		model.TaskTrackRequest	true	"task id and note"
 // @Success		200	{object} model.TaskTrack
 // @Router			/api/start-track [post]
 func (h *Handlers) StartTrackTask(w http.ResponseWriter, r *http.Request) {
@@ -44,15 +46,16 @@ func (h *Handlers) StartTrackTask(w http.ResponseWriter, r *http.Request) {
 
 	trackTimeEntity := model.TaskTrack{
 		TaskID: trackModel.TaskID,
-		Task: model.Task{
-			Base: model.Base{ID: trackModel.TaskID},
-		},
-		Base: model.Base{ID: trackId},
+		Base:   model.Base{ID: trackId},
+	}
+
+	if trackModel.Note != nil {
+		trackTimeEntity.Note = *trackModel.Note
 	}
 
 	trackTimeResponse, err := h.Storage.TrackTime(ctx, trackTimeEntity)
 	if err != nil {
-		h.Sender.JSON(w, http.StatusInternalServerError, err.Error())
+		h.sendError(w, err)
 		return
 	}
 
@@ -67,7 +70,7 @@ func (h *Handlers) StartTrackTask(w http.ResponseWriter, r *http.Request) {
 // @Tags		Track
 // @Produce		json
 // @Accept		json
-// @Param	id	body		model.TaskTrackRequest	true	"task id"
+// @Param	id	body		model.TaskTrackRequest	true	"task id and note"
 // @Success		200	{object} model.User
 // @Router			/api/end-track [post]
 func (h *Handlers) EndTrackTask(w http.ResponseWriter, r *http.Request) {
@@ -90,9 +93,9 @@ func (h *Handlers) EndTrackTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	trackTimeResponse, err := h.Storage.StopTrackTime(ctx, trackModel.TaskID)
+	trackTimeResponse, err := h.Storage.StopTrackTime(ctx, trackModel.TaskID, trackModel.Note)
 	if err != nil {
-		h.Sender.JSON(w, http.StatusInternalServerError, err.Error())
+		h.sendError(w, err)
 		return
 	}
 
@@ -102,19 +105,560 @@ func (h *Handlers) EndTrackTask(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// PauseTrackTask godoc
+// @Summary		Pause a running timer of task
+// @Tags		Track
+// @Produce		json
+// @Accept		json
+// @Param	id	body		model.TaskTrackRequest	true	"task id"
+// @Success		200	{object} model.TaskTrack
+// @Router			/api/pause-track [post]
+func (h *Handlers) PauseTrackTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var trackModel model.TaskTrackRequest
+
+	err := json.NewDecoder(r.Body).Decode(&trackModel)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = Validate.Struct(trackModel)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	trackTimeResponse, err := h.Storage.PauseTrackTime(ctx, trackModel.TaskID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackTimeResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ResumeTrackTask godoc
+// @Summary		Resume a paused timer of task
+// @Tags		Track
+// @Produce		json
+// @Accept		json
+// @Param	id	body		model.TaskTrackRequest	true	"task id"
+// @Success		200	{object} model.TaskTrack
+// @Router			/api/resume-track [post]
+func (h *Handlers) ResumeTrackTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var trackModel model.TaskTrackRequest
+
+	err := json.NewDecoder(r.Body).Decode(&trackModel)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = Validate.Struct(trackModel)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	trackTimeResponse, err := h.Storage.ResumeTrackTime(ctx, trackModel.TaskID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackTimeResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetTracks godoc
+// @Summary		Get tracked sessions with their pauses
+// @Tags		Track
+// @Produce		json,text/csv
+// @Success		200	{object} []model.TaskTrack
+// @Param limit query string false "pagination limit"
+// @Param page query string false "pagination page"
+// @Param sort query string false "pagination sort"
+// @Param task_id query string false "filter task id"
+// @Param user_id query string false "filter user id"
+// @Param note query string false "search in notes"
+// @Param from query string false "period start, RFC 3339"
+// @Param to query string false "period end, RFC 3339"
+// @Param format query string false "json (the default) or csv of the closed sessions cut to the period, without pagination; Accept: text/csv selects csv too"
+// @Router			/api/tracks [get]
+func (h *Handlers) GetTracks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filters model.TrackFilter
+	var pagination utils.Pagination
+
+	exportCSV, err := csvRequested(r)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filters.NoteFilter = r.URL.Query().Get("note")
+
+	if r.URL.Query().Get("from") != "" {
+		filters.FromFilter, err = time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.URL.Query().Get("to") != "" {
+		filters.ToFilter, err = time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.URL.Query().Get("task_id") != "" {
+		taskID, err := uuid.Parse(r.URL.Query().Get("task_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.TaskIDFilter = taskID
+	}
+
+	if r.URL.Query().Get("user_id") != "" {
+		userID, err := uuid.Parse(r.URL.Query().Get("user_id"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.UserIDFilter = userID
+	}
+
+	if exportCSV {
+		h.sendSessionsCSV(w, "tracks.csv", func(write func(model.SessionRow) error) error {
+			return h.Storage.ExportTracks(ctx, filters, write)
+		})
+		return
+	}
+
+	if r.URL.Query().Get("limit") != "" {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Limit = limit
+	}
+
+	if r.URL.Query().Get("page") != "" {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Page = page
+	}
+
+	pagination.Sort = r.URL.Query().Get("sort")
+	if pagination.Sort == "" {
+		pagination.Sort = "task_tracks.started_at desc"
+	}
+
+	tracks, err := h.Storage.GetTracks(ctx, filters, pagination)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, tracks)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddTrack godoc
+// @Summary		Add a time entry with explicit start and end, or start and duration
+// @Tags		Track
+// @Produce		json
+// @Accept		json
+// @Param	track request	body		model.AddTrackRequest	true	"time entry"
+// @Success		200	{object} model.TaskTrack
+// @Router			/api/tracks [post]
+func (h *Handlers) AddTrack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var trackModel model.AddTrackRequest
+
+	err := json.NewDecoder(r.Body).Decode(&trackModel)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(trackModel)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	endedAt := trackModel.EndedAt
+	if endedAt == nil {
+		duration, err := time.ParseDuration(trackModel.Duration)
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		end := trackModel.StartedAt.Add(duration)
+		endedAt = &end
+	}
+
+	trackTimeEntity := model.TaskTrack{
+		TaskID:    trackModel.TaskID,
+		StartedAt: trackModel.StartedAt,
+		EndedAt:   endedAt,
+		Note:      trackModel.Note,
+		Base:      model.Base{ID: uuid.New()},
+	}
+
+	trackTimeResponse, err := h.Storage.AddTrack(ctx, trackTimeEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackTimeResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateTrack godoc
+// @Summary		Edit the start and end of a stopped session
+// @Tags		Track
+// @Produce		json
+// @Accept		json
+// @Param	track request	body		model.UpdateTrackRequest	true	"session times"
+// @Success		200	{object} model.TaskTrack
+// @Router			/api/tracks [put]
+func (h *Handlers) UpdateTrack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var trackModel model.UpdateTrackRequest
+
+	err := json.NewDecoder(r.Body).Decode(&trackModel)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(trackModel)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	trackTimeEntity := model.TaskTrack{
+		StartedAt: trackModel.StartedAt,
+		EndedAt:   &trackModel.EndedAt,
+		Base:      model.Base{ID: trackModel.ID},
+	}
+
+	trackTimeResponse, err := h.Storage.UpdateTrack(ctx, trackTimeEntity)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackTimeResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SplitTrack godoc
+// @Summary		Split a stopped session in two, optionally moving the second part to another task
+// @Tags		Track
+// @Produce		json
+// @Accept		json
+// @Param	track request	body		model.SplitTrackRequest	true	"split instant"
+// @Success		200	{object} []model.TaskTrack
+// @Router			/api/tracks/split [post]
+func (h *Handlers) SplitTrack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var trackModel model.SplitTrackRequest
+
+	err := json.NewDecoder(r.Body).Decode(&trackModel)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(trackModel)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	trackTimeResponse, err := h.Storage.SplitTrack(ctx, trackModel.ID, trackModel.At, trackModel.TaskID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackTimeResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// MergeTracks godoc
+// @Summary		Merge adjacent stopped sessions of the same task
+// @Tags		Track
+// @Produce		json
+// @Accept		json
+// @Param	track request	body		model.MergeTracksRequest	true	"session ids"
+// @Success		200	{object} model.TaskTrack
+// @Router			/api/tracks/merge [post]
+func (h *Handlers) MergeTracks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var trackModel model.MergeTracksRequest
+
+	err := json.NewDecoder(r.Body).Decode(&trackModel)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(trackModel)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	trackTimeResponse, err := h.Storage.MergeTracks(ctx, trackModel.IDs)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackTimeResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetTrackRevisions godoc
+// @Summary		Get the previous values of an edited, split or merged session
+// @Tags		Track
+// @Produce		json
+// @Param	id	path		string	true	"session id"
+// @Success		200	{object} []model.TaskTrackRevision
+// @Router			/api/tracks/{id}/revisions [get]
+func (h *Handlers) GetTrackRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	trackID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	revisions, err := h.Storage.GetTrackRevisions(ctx, trackID)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, revisions)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetOverlaps godoc
+// @Summary		Get the sessions of a user that overlap in time
+// @Tags		Track
+// @Produce		json
+// @Success		200	{object} []model.TrackOverlap
+// @Param user_id query string true "user id"
+// @Param from query string false "period start, RFC 3339"
+// @Param to query string false "period end, RFC 3339"
+// @Router			/api/overlaps [get]
+func (h *Handlers) GetOverlaps(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := uuid.Parse(r.URL.Query().Get("user_id"))
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var from, to time.Time
+
+	if r.URL.Query().Get("from") != "" {
+		from, err = time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.URL.Query().Get("to") != "" {
+		to, err = time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	overlaps, err := h.Storage.GetOverlaps(ctx, userID, from, to)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, overlaps)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetTrackNote godoc
+// @Summary		Replace the note of a session
+// @Tags			Track
+// @Produce		json
+// @Accept			json
+// @Param	note request	body		model.SetNoteRequest	true	"session id and note"
+// @Success		200	{object} model.TaskTrack
+// @Router			/api/tracks/note [put]
+func (h *Handlers) SetTrackNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var note model.SetNoteRequest
+
+	err := json.NewDecoder(r.Body).Decode(&note)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(note)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	trackResponse, err := h.Storage.SetTrackNote(ctx, note.ID, note.Note)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetTrackBillable godoc
+// @Summary		Mark a session as billable or not
+// @Tags			Track
+// @Produce		json
+// @Accept			json
+// @Param	billable request	body		model.SetBillableRequest	true	"session id and billable flag"
+// @Success		200	{object} model.TaskTrack
+// @Router			/api/tracks/billable [put]
+func (h *Handlers) SetTrackBillable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var billable model.SetBillableRequest
+
+	err := json.NewDecoder(r.Body).Decode(&billable)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(billable)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	trackResponse, err := h.Storage.SetTrackBillable(ctx, billable.ID, billable.Billable)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, trackResponse)
+	if err != nil {
+		panic(err)
+	}
+}
+
 // CalcTime godoc
 // @Summary		Calculate a time spent on task
 // @Tags			Track
-// @Produce		json
+// @Produce		json,text/csv
 // @Accept			json
-// @Param	id	body		model.CalcTimeRequest	true	"user id"
-// @Success		200	"object,object"
+// @Param	id	body		model.CalcTimeRequest	true	"user id, project id or client id"
+// @Param duration_format query string false "primary duration format: hh_mm (the default), seconds or iso8601"
+// @Param format query string false "json (the default) or csv of the sessions of the report; Accept: text/csv selects csv too"
+// @Success		200	{object} []model.CalcTimeResult
 // @Router			/api/calc-time [post]
 func (h *Handlers) CalcTime(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var user model.CalcTimeRequest
 
-	err := json.NewDecoder(r.Body).Decode(&user)
+	format, err := durationFormat(r)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	exportCSV, err := csvRequested(r)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&user)
 	if err != nil {
 		h.Sender.JSON(w, http.StatusInternalServerError, err.Error())
 		return
@@ -130,29 +674,106 @@ func (h *Handlers) CalcTime(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	storageResult, err := h.Storage.CalcTime(ctx, user.ID)
+	if exportCSV {
+		h.sendSessionsCSV(w, "calc-time.csv", func(write func(model.SessionRow) error) error {
+			return h.Storage.ExportCalcTime(ctx, user, write)
+		})
+		return
+	}
+
+	storageResult, err := h.Storage.CalcTime(ctx, user)
 
 	if err != nil {
 		h.Sender.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	sort.Slice(storageResult[:], func(i, j int) bool {
-		return *storageResult[i].Time > *storageResult[j].Time
-	})
-
-	mapDuration := make(map[string]time.Duration)
+	result := make([]model.CalcTimeResult, 0, len(storageResult))
 
 	for _, el := range storageResult {
-		mapDuration[el.TaskID.String()] = *el.Time
+		id := el.ID
+		row := model.CalcTimeResult{
+			Total:         model.FormatDuration(el.Total, format),
+			Own:           model.FormatDuration(el.Time, format),
+			SessionsCount: el.SessionsCount,
+			PausesCount:   el.PausesCount,
+			PausedTime:    model.FormatDuration(el.PausedTime, format),
+		}
+
+		switch user.GroupBy {
+		case model.GroupByProject:
+			row.ProjectID, row.ProjectName = &id, el.Name
+		case model.GroupByClient:
+			row.ClientID, row.ClientName = &id, el.Name
+		default:
+			row.TaskID, row.TaskName = &id, el.Name
+		}
+
+		if el.SessionsCount > 0 {
+			firstStartedAt, lastEndedAt := el.FirstStartedAt, el.LastEndedAt
+			row.FirstStartedAt, row.LastEndedAt = &firstStartedAt, &lastEndedAt
+		}
+
+		result = append(result, row)
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, result)
+	if err != nil {
+		panic(err)
 	}
+}
 
-	result := make(map[string]string)
+// GetTimeBuckets godoc
+// @Summary		Calculate the time spent per day, week or month
+// @Tags			Track
+// @Produce		json
+// @Accept			json
+// @Param	request	body		model.BucketReportRequest	true	"user id or project id, bucket and period"
+// @Param duration_format query string false "primary duration format: hh_mm (the default), seconds or iso8601"
+// @Success		200	{object} []model.BucketReportRow
+// @Router			/api/calc-time/buckets [post]
+func (h *Handlers) GetTimeBuckets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var request model.BucketReportRequest
+
+	format, err := durationFormat(r)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	for k, v := range mapDuration {
-		out := time.Time{}.Add(v)
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-		result[k] = fmt.Sprintf(out.Format("15:04"))
+	err = Validate.Struct(request)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	totals, err := h.Storage.GetTimeBuckets(ctx, request)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	result := make([]model.BucketReportRow, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, model.BucketReportRow{
+			Bucket:      total.Bucket,
+			BucketStart: total.Start,
+			BucketEnd:   total.End,
+			TaskID:      total.TaskID,
+			TaskName:    total.TaskName,
+			Total:       model.FormatDuration(total.Time, format),
+		})
 	}
 
 	err = h.Sender.JSON(w, http.StatusOK, result)
@@ -160,3 +781,132 @@ func (h *Handlers) CalcTime(w http.ResponseWriter, r *http.Request) {
 		panic(err)
 	}
 }
+
+// GetTeamReport godoc
+// @Summary		Calculate the time spent by a team
+// @Tags			Track
+// @Produce		json
+// @Accept			json
+// @Param	request	body		model.TeamReportRequest	true	"user ids and period"
+// @Param name query string false "filter name"
+// @Param surname query string false "filter surname"
+// @Param address query string false "filter address"
+// @Param patronymic query string false "filter patronymic"
+// @Param limit query string false "pagination limit of the users"
+// @Param page query string false "pagination page of the users"
+// @Param sort query string false "sort of the users, time desc by default"
+// @Param duration_format query string false "primary duration format: hh_mm (the default), seconds or iso8601"
+// @Success		200	{object} model.TeamReport
+// @Router			/api/calc-time/team [post]
+func (h *Handlers) GetTeamReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var request model.TeamReportRequest
+	var filters model.UserFilter
+	var pagination utils.Pagination
+
+	format, err := durationFormat(r)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filters.NameFilter = r.URL.Query().Get("name")
+	filters.SurnameFilter = r.URL.Query().Get("surname")
+	filters.AddressFilter = r.URL.Query().Get("address")
+	filters.PatronymicFilter = r.URL.Query().Get("patronymic")
+
+	if r.URL.Query().Get("limit") != "" {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Limit = limit
+	}
+
+	if r.URL.Query().Get("page") != "" {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pagination.Page = page
+	}
+
+	pagination.Sort = r.URL.Query().Get("sort")
+	if pagination.Sort == "" {
+		pagination.Sort = "time desc, surname, name"
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.Sender.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = Validate.Struct(request)
+	if err != nil {
+		var errs []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errs = append(errs, err.Field()+" "+err.Tag())
+		}
+		h.Sender.JSON(w, http.StatusBadRequest, strings.Join(errs, ", "))
+		return
+	}
+
+	totals, pagination, err := h.Storage.GetTeamReport(ctx, request, filters, pagination)
+	if err != nil {
+		h.sendError(w, err)
+		return
+	}
+
+	report := model.TeamReport{
+		Users:         make([]model.TeamUserRow, 0, len(totals.Users)),
+		Tasks:         make([]model.TeamTaskRow, 0, len(totals.Tasks)),
+		Total:         model.FormatDuration(totals.Time, format),
+		SessionsCount: totals.SessionsCount,
+		Page:          pagination.GetPage(),
+		Limit:         pagination.GetLimit(),
+		TotalRows:     pagination.TotalRows,
+		TotalPages:    pagination.TotalPages,
+	}
+
+	for _, user := range totals.Users {
+		report.Users = append(report.Users, model.TeamUserRow{
+			UserID:        user.UserID,
+			Name:          user.Name,
+			Surname:       user.Surname,
+			Total:         model.FormatDuration(user.Time, format),
+			SessionsCount: user.SessionsCount,
+		})
+	}
+
+	for _, task := range totals.Tasks {
+		report.Tasks = append(report.Tasks, model.TeamTaskRow{
+			TaskID:        task.ID,
+			TaskName:      task.Name,
+			Total:         model.FormatDuration(task.Time, format),
+			SessionsCount: task.SessionsCount,
+		})
+	}
+
+	err = h.Sender.JSON(w, http.StatusOK, report)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// durationFormat reads the primary format of the durations of a report from the duration_format
+// query parameter.
+func durationFormat(r *http.Request) (string, error) {
+	format := r.URL.Query().Get("duration_format")
+
+	switch format {
+	case "":
+		return model.DurationFormatHHMM, nil
+	case model.DurationFormatHHMM, model.DurationFormatSeconds, model.DurationFormatISO8601:
+		return format, nil
+	}
+
+	return "", errors.New("duration_format must be hh_mm, seconds or iso8601")
+}
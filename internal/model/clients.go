@@ -0,0 +1,62 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Client is the db schema for the client table. A client owns projects and is billed for them in
+// its Currency. DefaultRate is the current hourly rate of the client; it applies to the projects
+// without a rate of their own.
+type Client struct {
+	Name        string           `json:"name" db:"name"`
+	Contact     string           `json:"contact" db:"contact"`
+	Currency    string           `json:"currency" gorm:"not null"`
+	DefaultRate *decimal.Decimal `json:"default_rate" gorm:"type:numeric(12,2)" swaggertype:"string" example:"42.50"`
+	Base
+}
+
+type AddClientRequest struct {
+	Name        string           `json:"name" validate:"required"`
+	Contact     string           `json:"contact"`
+	Currency    string           `json:"currency" validate:"required,iso4217" example:"EUR"`
+	DefaultRate *decimal.Decimal `json:"default_rate" swaggertype:"string" example:"42.50"`
+}
+
+type UpdateClientRequest struct {
+	ID          uuid.UUID        `json:"id" validate:"required"`
+	Name        string           `json:"name" validate:"required"`
+	Contact     string           `json:"contact"`
+	Currency    string           `json:"currency" validate:"required,iso4217" example:"EUR"`
+	DefaultRate *decimal.Decimal `json:"default_rate" swaggertype:"string" example:"42.50"`
+}
+
+type DeleteClientRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+type ClientFilter struct {
+	NameFilter string
+}
+
+// ClientReportRequest selects the sessions of the projects of ClientID, or of all clients, in the
+// period [From, To]. The time and its billable part are both cut to the period as in calc-time;
+// rates apply by the start of the whole session. A zero From or To leaves the period open.
+type ClientReportRequest struct {
+	ClientID uuid.UUID `json:"client_id"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+}
+
+// ClientTotal is the time tracked for a client and the amount of its billable part. Unrated is the
+// billable time done while no rate applied, it isn't in Amount.
+type ClientTotal struct {
+	ClientID     uuid.UUID       `json:"client_id"`
+	Name         string          `json:"name"`
+	Currency     string          `json:"currency"`
+	Time         time.Duration   `json:"time"`
+	BillableTime time.Duration   `json:"billable_time"`
+	Unrated      time.Duration   `json:"unrated"`
+	Amount       decimal.Decimal `json:"amount" swaggertype:"string" example:"42.50"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats of the durations in the reports.
+const (
+	DurationFormatSeconds = "seconds"
+	DurationFormatHHMM    = "hh_mm"
+	DurationFormatISO8601 = "iso8601"
+)
+
+// Duration is a duration in every format of the reports. Value repeats it in the primary format
+// chosen by the request: a number of seconds, or a string.
+type Duration struct {
+	Value   interface{} `json:"value" swaggertype:"string" example:"26:05"`
+	Seconds int64       `json:"seconds" example:"93900"`
+	HHMM    string      `json:"hh_mm" example:"26:05"`
+	ISO8601 string      `json:"iso8601" example:"PT26H5M"`
+}
+
+// FormatDuration formats d for a report with primary as its primary format.
+func FormatDuration(d time.Duration, primary string) Duration {
+	seconds := int64(d / time.Second)
+
+	duration := Duration{
+		Seconds: seconds,
+		HHMM:    fmt.Sprintf("%02d:%02d", seconds/3600, seconds%3600/60),
+		ISO8601: formatISO8601(seconds),
+	}
+
+	switch primary {
+	case DurationFormatSeconds:
+		duration.Value = duration.Seconds
+	case DurationFormatISO8601:
+		duration.Value = duration.ISO8601
+	default:
+		duration.Value = duration.HHMM
+	}
+
+	return duration
+}
+
+// formatISO8601 formats the seconds as an ISO 8601 duration of hours, minutes and seconds,
+// like PT26H5M. Hours aren't carried over into days, as days differ in length.
+func formatISO8601(seconds int64) string {
+	if seconds == 0 {
+		return "PT0S"
+	}
+
+	var builder strings.Builder
+	builder.WriteString("PT")
+
+	if hours := seconds / 3600; hours > 0 {
+		fmt.Fprintf(&builder, "%dH", hours)
+	}
+
+	if minutes := seconds % 3600 / 60; minutes > 0 {
+		fmt.Fprintf(&builder, "%dM", minutes)
+	}
+
+	if seconds%60 > 0 {
+		fmt.Fprintf(&builder, "%dS", seconds%60)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       time.Duration
+		seconds int64
+		hhmm    string
+		iso8601 string
+	}{
+		{"zero", 0, 0, "00:00", "PT0S"},
+		{"under a second", 999 * time.Millisecond, 0, "00:00", "PT0S"},
+		{"seconds", 42 * time.Second, 42, "00:00", "PT42S"},
+		{"minutes and seconds", 5*time.Minute + 7*time.Second, 307, "00:05", "PT5M7S"},
+		{"whole hours", 3 * time.Hour, 10800, "03:00", "PT3H"},
+		{"a whole day", 24 * time.Hour, 86400, "24:00", "PT24H"},
+		{"over a day", 26*time.Hour + 5*time.Minute, 93900, "26:05", "PT26H5M"},
+		{"over a hundred hours", 123*time.Hour + 4*time.Minute + 5*time.Second, 443045, "123:04", "PT123H4M5S"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			duration := FormatDuration(test.d, DurationFormatHHMM)
+
+			if duration.Seconds != test.seconds {
+				t.Errorf("Seconds = %d, want %d", duration.Seconds, test.seconds)
+			}
+
+			if duration.HHMM != test.hhmm {
+				t.Errorf("HHMM = %q, want %q", duration.HHMM, test.hhmm)
+			}
+
+			if duration.ISO8601 != test.iso8601 {
+				t.Errorf("ISO8601 = %q, want %q", duration.ISO8601, test.iso8601)
+			}
+		})
+	}
+}
+
+func TestFormatDurationPrimary(t *testing.T) {
+	d := 26*time.Hour + 5*time.Minute
+
+	tests := []struct {
+		primary string
+		value   interface{}
+	}{
+		{"", "26:05"},
+		{DurationFormatHHMM, "26:05"},
+		{DurationFormatSeconds, int64(93900)},
+		{DurationFormatISO8601, "PT26H5M"},
+	}
+
+	for _, test := range tests {
+		if value := FormatDuration(d, test.primary).Value; value != test.value {
+			t.Errorf("Value in %q = %v, want %v", test.primary, value, test.value)
+		}
+	}
+}
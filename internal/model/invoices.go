@@ -0,0 +1,91 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Invoice is the db schema for the invoice table. An invoice bills the billable sessions of the
+// projects of a client started within [PeriodFrom, PeriodTo]. Number is sequential over all
+// invoices. The billed sessions reference the invoice and can't be edited or billed again, unless
+// the invoice is void.
+type Invoice struct {
+	Number     int             `json:"number" gorm:"uniqueIndex;not null"`
+	ClientID   uuid.UUID       `json:"client_id" gorm:"index"`
+	Client     *Client         `json:"client,omitempty"`
+	PeriodFrom time.Time       `json:"period_from"`
+	PeriodTo   time.Time       `json:"period_to"`
+	GroupBy    string          `json:"group_by" gorm:"not null"`
+	Status     string          `json:"status" gorm:"not null;default:draft"`
+	Currency   string          `json:"currency" gorm:"not null"`
+	Time       time.Duration   `json:"time"`
+	Total      decimal.Decimal `json:"total" gorm:"type:numeric(14,2);not null" swaggertype:"string" example:"1250.00"`
+	IssuedAt   *time.Time      `json:"issued_at"`
+	PaidAt     *time.Time      `json:"paid_at"`
+	Lines      []InvoiceLine   `json:"lines,omitempty"`
+	Base
+}
+
+// InvoiceLine is the db schema for the invoice_line table. A line bills the sessions of one project
+// or one task of the invoice. Notes are the notes of the sessions, what was done.
+type InvoiceLine struct {
+	InvoiceID   uuid.UUID       `json:"invoice_id" gorm:"index"`
+	ProjectID   *uuid.UUID      `json:"project_id"`
+	TaskID      *uuid.UUID      `json:"task_id"`
+	Description string          `json:"description"`
+	Notes       string          `json:"notes"`
+	Time        time.Duration   `json:"time"`
+	Amount      decimal.Decimal `json:"amount" gorm:"type:numeric(14,2);not null" swaggertype:"string" example:"420.00"`
+	Base
+}
+
+// Hours is the billed time of the line in hours, rounded to hundredths.
+func (l *InvoiceLine) Hours() decimal.Decimal {
+	return decimal.NewFromInt(int64(l.Time)).Div(decimal.NewFromInt(int64(time.Hour))).Round(2)
+}
+
+// Statuses of an invoice.
+const (
+	InvoiceStatusDraft  = "draft"
+	InvoiceStatusIssued = "issued"
+	InvoiceStatusPaid   = "paid"
+	InvoiceStatusVoid   = "void"
+)
+
+// invoiceTransitions lists the statuses an invoice can move to from each status. Paid and void
+// invoices are final.
+var invoiceTransitions = map[string][]string{
+	InvoiceStatusDraft:  {InvoiceStatusIssued, InvoiceStatusVoid},
+	InvoiceStatusIssued: {InvoiceStatusPaid, InvoiceStatusVoid},
+}
+
+// CanMoveTo reports whether the invoice can change its status to status.
+func (i *Invoice) CanMoveTo(status string) bool {
+	for _, allowed := range invoiceTransitions[i.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddInvoiceRequest bills the unbilled billable sessions of the client started within [From, To].
+// GroupBy makes a line per "project" (the default) or per "task".
+type AddInvoiceRequest struct {
+	ClientID uuid.UUID `json:"client_id" validate:"required"`
+	From     time.Time `json:"from" validate:"required"`
+	To       time.Time `json:"to" validate:"required,gtfield=From"`
+	GroupBy  string    `json:"group_by" validate:"omitempty,oneof=task project"`
+}
+
+type UpdateInvoiceStatusRequest struct {
+	ID     uuid.UUID `json:"id" validate:"required"`
+	Status string    `json:"status" validate:"required,oneof=draft issued paid void"`
+}
+
+type InvoiceFilter struct {
+	ClientIDFilter uuid.UUID
+	StatusFilter   string
+}
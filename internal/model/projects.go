@@ -0,0 +1,35 @@
+package model
+
+import "github.com/google/uuid"
+
+// Project is the db schema for the project table. A project groups tasks of any users and
+// belongs to the client it is done for.
+type Project struct {
+	Name        string     `json:"name" db:"name"`
+	Description string     `json:"description" db:"description"`
+	ClientID    *uuid.UUID `json:"client_id" gorm:"index"`
+	Client      *Client    `json:"client,omitempty"`
+	Base
+}
+
+type AddProjectRequest struct {
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description"`
+	ClientID    *uuid.UUID `json:"client_id"`
+}
+
+type UpdateProjectRequest struct {
+	ID          uuid.UUID  `json:"id" validate:"required"`
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description"`
+	ClientID    *uuid.UUID `json:"client_id"`
+}
+
+type DeleteProjectRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+type ProjectFilter struct {
+	NameFilter     string
+	ClientIDFilter uuid.UUID
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Rate is the db schema for the rate table. A rate is the hourly amount of the work of a user, for a
+// client, on a project or on a task, from StartsAt until the next rate of the same target starts.
+// Rates are never changed, a new rate replaces the old one for the work done after it starts.
+type Rate struct {
+	UserID    *uuid.UUID      `json:"user_id" gorm:"index"`
+	ClientID  *uuid.UUID      `json:"client_id" gorm:"index"`
+	ProjectID *uuid.UUID      `json:"project_id" gorm:"index"`
+	TaskID    *uuid.UUID      `json:"task_id" gorm:"index"`
+	Amount    decimal.Decimal `json:"amount" gorm:"type:numeric(12,2);not null" swaggertype:"string" example:"42.50"`
+	StartsAt  time.Time       `json:"starts_at" gorm:"not null"`
+	Base
+}
+
+// Target is the user, the client, the project or the task the rate is set for.
+func (r *Rate) Target() uuid.UUID {
+	switch {
+	case r.TaskID != nil:
+		return *r.TaskID
+	case r.ProjectID != nil:
+		return *r.ProjectID
+	case r.ClientID != nil:
+		return *r.ClientID
+	default:
+		return *r.UserID
+	}
+}
+
+// AddRateRequest sets a rate for exactly one of the user, the project or the task. The rate
+// starts now, or at StartsAt in the future. The rates of a client are set with its default rate.
+type AddRateRequest struct {
+	UserID    *uuid.UUID      `json:"user_id" validate:"required_without_all=ProjectID TaskID,excluded_with=ProjectID TaskID"`
+	ProjectID *uuid.UUID      `json:"project_id" validate:"required_without_all=UserID TaskID,excluded_with=UserID TaskID"`
+	TaskID    *uuid.UUID      `json:"task_id" validate:"required_without_all=UserID ProjectID,excluded_with=UserID ProjectID"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"42.50"`
+	StartsAt  *time.Time      `json:"starts_at"`
+}
+
+type RateFilter struct {
+	UserIDFilter    uuid.UUID
+	ClientIDFilter  uuid.UUID
+	ProjectIDFilter uuid.UUID
+	TaskIDFilter    uuid.UUID
+}
+
+// EarningsRequest selects the billable sessions of the user ID, of the project ProjectID, or of
+// the user in the project, started within [From, To]. A zero From or To leaves the period open.
+type EarningsRequest struct {
+	ID        uuid.UUID `json:"id" validate:"required_without=ProjectID"`
+	ProjectID uuid.UUID `json:"project_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+}
+
+// TaskEarnings is the billable time of a task and the amount earned with it, in the currency of the
+// client of the task, empty for a task without a client. Unrated is the billable time done while no
+// rate applied, it isn't in Amount.
+type TaskEarnings struct {
+	TaskID    uuid.UUID       `json:"task_id"`
+	TaskName  string          `json:"task_name"`
+	ProjectID *uuid.UUID      `json:"project_id"`
+	Currency  string          `json:"currency"`
+	Time      time.Duration   `json:"time"`
+	Unrated   time.Duration   `json:"unrated"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"42.50"`
+}
+
+// CurrencyEarnings sums the earnings of the tasks in a currency.
+type CurrencyEarnings struct {
+	Currency string          `json:"currency"`
+	Time     time.Duration   `json:"time"`
+	Unrated  time.Duration   `json:"unrated"`
+	Amount   decimal.Decimal `json:"amount" swaggertype:"string" example:"42.50"`
+}
+
+// EarningsReport is the earnings per task, by currency and the most earned first, with their sums
+// per currency. Amounts in different currencies are never added up, Time and Unrated are the sums
+// over all of the tasks.
+type EarningsReport struct {
+	Tasks      []TaskEarnings     `json:"tasks"`
+	Currencies []CurrencyEarnings `json:"currencies"`
+	Time       time.Duration      `json:"time"`
+	Unrated    time.Duration      `json:"unrated"`
+}
+
+// SetBillableRequest marks the session ID as billable or not.
+type SetBillableRequest struct {
+	ID       uuid.UUID `json:"id" validate:"required"`
+	Billable bool      `json:"billable"`
+}
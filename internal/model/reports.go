@@ -0,0 +1,156 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Calendar buckets of the time reports.
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// BucketReportRequest selects the closed sessions of the user ID, of the project ProjectID, or of
+// the user in the project, and sums their time within [From, To) per calendar Bucket and task.
+// Days, ISO weeks and months begin at midnight in Timezone, an IANA name, UTC by default.
+type BucketReportRequest struct {
+	ID        uuid.UUID `json:"id" validate:"required_without=ProjectID"`
+	ProjectID uuid.UUID `json:"project_id"`
+	Bucket    string    `json:"bucket" validate:"required,oneof=day week month"`
+	Timezone  string    `json:"timezone" validate:"omitempty,timezone"`
+	From      time.Time `json:"from" validate:"required"`
+	To        time.Time `json:"to" validate:"required,gtfield=From"`
+}
+
+// BucketTotal is the time tracked on a task within a calendar bucket. Bucket is its label:
+// 2006-01-02 for a day, 2006-W01 for an ISO week and 2006-01 for a month.
+type BucketTotal struct {
+	Bucket   string
+	Start    time.Time
+	End      time.Time
+	TaskID   uuid.UUID
+	TaskName string
+	Time     time.Duration
+}
+
+// BucketReportRow is a row of the report by calendar buckets.
+type BucketReportRow struct {
+	Bucket      string    `json:"bucket"`
+	BucketStart time.Time `json:"bucket_start"`
+	BucketEnd   time.Time `json:"bucket_end"`
+	TaskID      uuid.UUID `json:"task_id"`
+	TaskName    string    `json:"task_name"`
+	Total       Duration  `json:"total"`
+}
+
+// BucketStart returns the beginning of the day, the ISO week or the month that t falls in, in the
+// location of t.
+func BucketStart(bucket string, t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	switch bucket {
+	case BucketWeek:
+		// ISO weeks begin on Monday.
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	case BucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// NextBucket returns the beginning of the bucket that follows the one beginning at start.
+func NextBucket(bucket string, start time.Time) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// BucketLabel names the bucket beginning at start.
+func BucketLabel(bucket string, start time.Time) string {
+	switch bucket {
+	case BucketWeek:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case BucketMonth:
+		return start.Format("2006-01")
+	default:
+		return start.Format("2006-01-02")
+	}
+}
+
+// TeamReportRequest selects the closed sessions of the users UserIDs, of all users without them,
+// and sums their time within the period. A zero From or To leaves it open on that side.
+type TeamReportRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids"`
+	From    time.Time   `json:"from"`
+	To      time.Time   `json:"to" validate:"omitempty,gtfield=From"`
+}
+
+// UserTotal is the time tracked by a user in the closed sessions of all of their tasks.
+type UserTotal struct {
+	UserID        uuid.UUID
+	Name          string
+	Surname       string
+	Time          time.Duration
+	SessionsCount int
+}
+
+// TeamTotals are the totals of the team report. Users holds a page of the users, Tasks and the
+// grand total cover every user of the report. The time of a task is its own, without subtasks,
+// so that the task totals add up to the grand total.
+type TeamTotals struct {
+	Users         []UserTotal
+	Tasks         []TimeTotal
+	Time          time.Duration
+	SessionsCount int
+}
+
+// TeamUserRow is a user row of the team report.
+type TeamUserRow struct {
+	UserID        uuid.UUID `json:"user_id"`
+	Name          string    `json:"name"`
+	Surname       string    `json:"surname"`
+	Total         Duration  `json:"total"`
+	SessionsCount int       `json:"sessions_count"`
+}
+
+// TeamTaskRow is a task row of the team report.
+type TeamTaskRow struct {
+	TaskID        uuid.UUID `json:"task_id"`
+	TaskName      string    `json:"task_name"`
+	Total         Duration  `json:"total"`
+	SessionsCount int       `json:"sessions_count"`
+}
+
+// TeamReport is the team report with the page of the user rows.
+type TeamReport struct {
+	Users         []TeamUserRow `json:"users"`
+	Tasks         []TeamTaskRow `json:"tasks"`
+	Total         Duration      `json:"total"`
+	SessionsCount int           `json:"sessions_count"`
+	Page          int           `json:"page"`
+	Limit         int           `json:"limit"`
+	TotalRows     int64         `json:"total_rows"`
+	TotalPages    int           `json:"total_pages"`
+}
+
+// SessionRow is a closed session of the detailed exports, cut to the period of the export.
+type SessionRow struct {
+	ID          uuid.UUID
+	UserName    string
+	UserSurname string
+	TaskName    string
+	StartedAt   time.Time
+	EndedAt     time.Time
+	Time        time.Duration
+	Note        string
+}
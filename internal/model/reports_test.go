@@ -0,0 +1,117 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuckets(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		bucket string
+		t      time.Time
+		start  time.Time
+		length time.Duration
+		label  string
+	}{
+		{"day", BucketDay, time.Date(2024, time.March, 5, 15, 4, 5, 0, time.UTC),
+			time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), 24 * time.Hour, "2024-03-05"},
+		{"spring forward day", BucketDay, time.Date(2024, time.March, 31, 12, 0, 0, 0, berlin),
+			time.Date(2024, time.March, 31, 0, 0, 0, 0, berlin), 23 * time.Hour, "2024-03-31"},
+		{"fall back day", BucketDay, time.Date(2024, time.November, 3, 23, 30, 0, 0, newYork),
+			time.Date(2024, time.November, 3, 0, 0, 0, 0, newYork), 25 * time.Hour, "2024-11-03"},
+		{"week from Sunday", BucketWeek, time.Date(2024, time.March, 10, 23, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), 7 * 24 * time.Hour, "2024-W10"},
+		{"week from Monday", BucketWeek, time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), 7 * 24 * time.Hour, "2024-W10"},
+		{"week with spring forward", BucketWeek, time.Date(2024, time.March, 27, 9, 0, 0, 0, berlin),
+			time.Date(2024, time.March, 25, 0, 0, 0, 0, berlin), 7*24*time.Hour - time.Hour, "2024-W13"},
+		{"week 53", BucketWeek, time.Date(2021, time.January, 3, 12, 0, 0, 0, time.UTC),
+			time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC), 7 * 24 * time.Hour, "2020-W53"},
+		{"week 1 beginning in December", BucketWeek, time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC),
+			time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), 7 * 24 * time.Hour, "2025-W01"},
+		{"month", BucketMonth, time.Date(2024, time.February, 29, 23, 59, 0, 0, time.UTC),
+			time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), 29 * 24 * time.Hour, "2024-02"},
+		{"month with fall back", BucketMonth, time.Date(2024, time.October, 31, 23, 0, 0, 0, berlin),
+			time.Date(2024, time.October, 1, 0, 0, 0, 0, berlin), 31*24*time.Hour + time.Hour, "2024-10"},
+		{"December", BucketMonth, time.Date(2024, time.December, 31, 23, 0, 0, 0, time.UTC),
+			time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC), 31 * 24 * time.Hour, "2024-12"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := BucketStart(test.bucket, test.t)
+			if !start.Equal(test.start) || start.Location() != test.start.Location() {
+				t.Errorf("BucketStart() = %s, want %s", start, test.start)
+			}
+
+			if length := NextBucket(test.bucket, start).Sub(start); length != test.length {
+				t.Errorf("bucket lasts %s, want %s", length, test.length)
+			}
+
+			if label := BucketLabel(test.bucket, start); label != test.label {
+				t.Errorf("BucketLabel() = %q, want %q", label, test.label)
+			}
+		})
+	}
+}
+
+func TestParseISOWeek(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		week     string
+		location *time.Location
+		monday   time.Time
+		invalid  bool
+	}{
+		{"2024-W12", time.UTC, time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC), false},
+		{"2024-W13", berlin, time.Date(2024, time.March, 25, 0, 0, 0, 0, berlin), false},
+		{"2025-W01", time.UTC, time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), false},
+		{"2020-W53", time.UTC, time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC), false},
+		{"2026-W53", time.UTC, time.Date(2026, time.December, 28, 0, 0, 0, 0, time.UTC), false},
+		{"2021-W53", time.UTC, time.Time{}, true},
+		{"2024-W00", time.UTC, time.Time{}, true},
+		{"2024-W1", time.UTC, time.Time{}, true},
+		{"2024-12", time.UTC, time.Time{}, true},
+		{"", time.UTC, time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.week, func(t *testing.T) {
+			monday, err := ParseISOWeek(test.week, test.location)
+			if test.invalid {
+				if err == nil {
+					t.Errorf("ParseISOWeek() = %s, want an error", monday)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !monday.Equal(test.monday) || monday.Location() != test.location {
+				t.Errorf("ParseISOWeek() = %s, want %s", monday, test.monday)
+			}
+
+			if label := BucketLabel(BucketWeek, monday); label != test.week {
+				t.Errorf("week of the Monday is %s", label)
+			}
+		})
+	}
+}
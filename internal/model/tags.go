@@ -0,0 +1,29 @@
+package model
+
+import "github.com/google/uuid"
+
+// Tag is the db schema for the tag table. Tags label tasks and single sessions
+// independently of the task names, like meeting, bugfix or support.
+type Tag struct {
+	Name string `json:"name" db:"name" gorm:"uniqueIndex:idx_tags_name,where:deleted_at IS NULL"`
+	Base
+}
+
+type AddTagRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type UpdateTagRequest struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Name string    `json:"name" validate:"required"`
+}
+
+type DeleteTagRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// SetTagsRequest replaces the tags of the task or the session ID.
+type SetTagsRequest struct {
+	ID     uuid.UUID   `json:"id" validate:"required"`
+	TagIDs []uuid.UUID `json:"tag_ids" validate:"dive,required"`
+}
@@ -2,20 +2,439 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"sort"
 	"time"
 )
 
+// TaskTrack is a single tracking session of a task. A session is open while EndedAt is nil.
+// Time is the tracked duration of a closed session without the paused time. A session is billable
+// when its task is, unless it is changed for the session. A session billed on InvoiceID can't be
+// changed. Note describes what was done in the session. ImportUID identifies the calendar event
+// an imported session was made of.
 type TaskTrack struct {
-	TaskID uuid.UUID
-	Task   Task
-	Time   *time.Duration
+	TaskID      uuid.UUID `gorm:"index;index:idx_task_tracks_open,unique,where:ended_at IS NULL AND deleted_at IS NULL"`
+	Task        Task
+	Time        *time.Duration
+	StartedAt   time.Time        `json:"started_at"`
+	EndedAt     *time.Time       `json:"ended_at"`
+	Source      string           `json:"source" gorm:"not null;default:timer"`
+	Overlapping bool             `json:"overlapping" gorm:"not null;default:false"`
+	Billable    bool             `json:"billable" gorm:"not null;default:false"`
+	InvoiceID   *uuid.UUID       `json:"invoice_id" gorm:"index"`
+	Note        string           `json:"note" gorm:"not null;default:''"`
+	ImportUID   string           `json:"import_uid,omitempty" gorm:"not null;default:'';index"`
+	PausesCount int              `json:"pauses_count" gorm:"not null;default:0"`
+	PausedTime  time.Duration    `json:"paused_time" gorm:"not null;default:0"`
+	Pauses      []TaskTrackPause `json:"pauses,omitempty"`
+	Tags        []Tag            `json:"tags,omitempty" gorm:"many2many:task_track_tags"`
+	AutoStopped []TaskTrack      `json:"auto_stopped,omitempty" gorm:"-"`
 	Base
 }
 
+// TaskTrackRevision keeps the values a session had before it was edited, split or merged, before
+// its note was changed, or before a timesheet update changed it. RelatedTrackID is the session
+// created by a split or absorbed by a merge.
+type TaskTrackRevision struct {
+	TaskTrackID    uuid.UUID      `json:"task_track_id" gorm:"index"`
+	Action         string         `json:"action"`
+	RelatedTrackID *uuid.UUID     `json:"related_track_id"`
+	TaskID         uuid.UUID      `json:"task_id"`
+	StartedAt      time.Time      `json:"started_at"`
+	EndedAt        *time.Time     `json:"ended_at"`
+	Time           *time.Duration `json:"time"`
+	PausesCount    int            `json:"pauses_count"`
+	PausedTime     time.Duration  `json:"paused_time"`
+	Note           string         `json:"note"`
+	Base
+}
+
+// Actions recorded in the revisions of a session. A timesheet revision is of a manual session
+// shortened or removed by a timesheet update.
+const (
+	TrackActionEdit      = "edit"
+	TrackActionSplit     = "split"
+	TrackActionMerge     = "merge"
+	TrackActionNote      = "note"
+	TrackActionTimesheet = "timesheet"
+)
+
+// Sources of the sessions. Timer sessions are started and stopped by the user, manual ones are
+// entered afterwards with explicit times, and imported ones are made of calendar events.
+const (
+	TrackSourceTimer  = "timer"
+	TrackSourceManual = "manual"
+	TrackSourceImport = "import"
+)
+
+// TaskTrackPause is an interval during which a session was paused. A pause is running while ResumedAt is nil.
+type TaskTrackPause struct {
+	TaskTrackID uuid.UUID  `json:"task_track_id" gorm:"index"`
+	PausedAt    time.Time  `json:"paused_at"`
+	ResumedAt   *time.Time `json:"resumed_at"`
+	Base
+}
+
+// RunningPause returns the pause that has not been resumed yet, or nil if the session is running.
+func (t *TaskTrack) RunningPause() *TaskTrackPause {
+	for i := range t.Pauses {
+		if t.Pauses[i].ResumedAt == nil {
+			return &t.Pauses[i]
+		}
+	}
+
+	return nil
+}
+
+// Close ends the session at endedAt, resuming a running pause, and calculates its duration.
+func (t *TaskTrack) Close(endedAt time.Time) {
+	if pause := t.RunningPause(); pause != nil {
+		pause.ResumedAt = &endedAt
+	}
+
+	t.EndedAt = &endedAt
+	t.Recalculate()
+}
+
+// Recalculate updates the pause totals from Pauses and, for a closed session, its duration.
+func (t *TaskTrack) Recalculate() {
+	t.PausesCount = len(t.Pauses)
+	t.PausedTime = 0
+
+	for _, pause := range t.Pauses {
+		if pause.ResumedAt != nil {
+			t.PausedTime += pause.ResumedAt.Sub(pause.PausedAt)
+		}
+	}
+
+	if t.EndedAt != nil {
+		duration := t.EndedAt.Sub(t.StartedAt) - t.PausedTime
+		t.Time = &duration
+	}
+}
+
+// Interval is a period of time from Start to End.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ActiveIntervals returns the periods of a closed session during which it was not paused.
+func (t *TaskTrack) ActiveIntervals() []Interval {
+	pauses := make([]TaskTrackPause, len(t.Pauses))
+	copy(pauses, t.Pauses)
+	sort.Slice(pauses, func(i, j int) bool {
+		return pauses[i].PausedAt.Before(pauses[j].PausedAt)
+	})
+
+	var intervals []Interval
+	start := t.StartedAt
+
+	for _, pause := range pauses {
+		if pause.PausedAt.After(start) {
+			intervals = append(intervals, Interval{Start: start, End: pause.PausedAt})
+		}
+
+		if pause.ResumedAt != nil && pause.ResumedAt.After(start) {
+			start = *pause.ResumedAt
+		}
+	}
+
+	if t.EndedAt != nil && t.EndedAt.After(start) {
+		intervals = append(intervals, Interval{Start: start, End: *t.EndedAt})
+	}
+
+	return intervals
+}
+
+// Clip returns the part of the interval within [from, to], and false when nothing of it is
+// there. A zero from or to leaves the period open on that side.
+func (i Interval) Clip(from time.Time, to time.Time) (Interval, bool) {
+	if !from.IsZero() && i.Start.Before(from) {
+		i.Start = from
+	}
+
+	if !to.IsZero() && i.End.After(to) {
+		i.End = to
+	}
+
+	return i, i.Start.Before(i.End)
+}
+
+// Overlaps reports whether two closed sessions were active at the same time.
+func (t *TaskTrack) Overlaps(other *TaskTrack) bool {
+	for _, interval := range t.ActiveIntervals() {
+		for _, otherInterval := range other.ActiveIntervals() {
+			if interval.Start.Before(otherInterval.End) && otherInterval.Start.Before(interval.End) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SetPeriod moves the bounds of a closed session. Pauses are cut to the new bounds and the ones
+// left outside of them are dropped.
+func (t *TaskTrack) SetPeriod(startedAt time.Time, endedAt time.Time) {
+	var pauses []TaskTrackPause
+
+	for _, pause := range t.Pauses {
+		if pause.PausedAt.Before(startedAt) {
+			pause.PausedAt = startedAt
+		}
+
+		if pause.ResumedAt.After(endedAt) {
+			pause.ResumedAt = &endedAt
+		}
+
+		if pause.ResumedAt.After(pause.PausedAt) {
+			pauses = append(pauses, pause)
+		}
+	}
+
+	t.StartedAt = startedAt
+	t.EndedAt = &endedAt
+	t.Pauses = pauses
+	t.Recalculate()
+}
+
+// Split cuts a closed session at the instant at. The session keeps the part before at, the part
+// after it is returned as a new session with the given id.
+func (t *TaskTrack) Split(at time.Time, id uuid.UUID) TaskTrack {
+	second := TaskTrack{
+		TaskID:   t.TaskID,
+		Source:   t.Source,
+		Billable: t.Billable,
+		Note:     t.Note,
+		Base:     Base{ID: id},
+	}
+
+	for _, pause := range t.Pauses {
+		pause.TaskTrackID = id
+		pause.Base = Base{ID: uuid.New()}
+		second.Pauses = append(second.Pauses, pause)
+	}
+
+	second.SetPeriod(at, *t.EndedAt)
+	t.SetPeriod(t.StartedAt, at)
+
+	return second
+}
+
+// Merge appends the closed session next, which starts after t ends, to t. The gap between them
+// becomes a pause, so the tracked duration is the sum of both sessions. The notes of both are kept.
+func (t *TaskTrack) Merge(next TaskTrack) {
+	if next.StartedAt.After(*t.EndedAt) {
+		gapStartedAt := *t.EndedAt
+		gapEndedAt := next.StartedAt
+
+		t.Pauses = append(t.Pauses, TaskTrackPause{
+			TaskTrackID: t.ID,
+			PausedAt:    gapStartedAt,
+			ResumedAt:   &gapEndedAt,
+			Base:        Base{ID: uuid.New()},
+		})
+	}
+
+	for _, pause := range next.Pauses {
+		pause.TaskTrackID = t.ID
+		pause.Base = Base{ID: uuid.New()}
+		t.Pauses = append(t.Pauses, pause)
+	}
+
+	switch {
+	case t.Note == "":
+		t.Note = next.Note
+	case next.Note != "" && next.Note != t.Note:
+		t.Note += "; " + next.Note
+	}
+
+	t.EndedAt = next.EndedAt
+	t.Recalculate()
+}
+
+// Revision creates a revision with the current values of the session.
+func (t *TaskTrack) Revision(action string, relatedTrackID *uuid.UUID) TaskTrackRevision {
+	return TaskTrackRevision{
+		TaskTrackID:    t.ID,
+		Action:         action,
+		RelatedTrackID: relatedTrackID,
+		TaskID:         t.TaskID,
+		StartedAt:      t.StartedAt,
+		EndedAt:        t.EndedAt,
+		Time:           t.Time,
+		PausesCount:    t.PausesCount,
+		PausedTime:     t.PausedTime,
+		Note:           t.Note,
+		Base:           Base{ID: uuid.New()},
+	}
+}
+
+// TaskTrackRequest starts or stops the timer of the task. Note, when it is set, describes the work
+// of the session; it is ignored by pause and resume.
 type TaskTrackRequest struct {
 	TaskID uuid.UUID `json:"task_id"`
+	Note   *string   `json:"note"`
+}
+
+// AddTrackRequest creates a closed session with explicit times. Either EndedAt or Duration
+// (like "1h30m") is set.
+type AddTrackRequest struct {
+	TaskID    uuid.UUID  `json:"task_id" validate:"required"`
+	StartedAt time.Time  `json:"started_at" validate:"required"`
+	EndedAt   *time.Time `json:"ended_at" validate:"required_without=Duration,excluded_with=Duration"`
+	Duration  string     `json:"duration" validate:"required_without=EndedAt"`
+	Note      string     `json:"note"`
+}
+
+// ImportTracksRequest turns the events of an uploaded iCalendar file that start within [From, To)
+// into sessions of the task TaskID. Recurring events are expanded within the period. With UIDs, only
+// the events or the occurrences with those UIDs are imported. Times without a time zone are read in Timezone, an IANA
+// name, UTC by default. With Preview nothing is stored.
+type ImportTracksRequest struct {
+	TaskID   uuid.UUID `validate:"required"`
+	From     time.Time `validate:"required"`
+	To       time.Time `validate:"required,gtfield=From"`
+	UIDs     []string
+	Timezone string `validate:"omitempty,timezone"`
+	Preview  bool
+}
+
+// ImportEvent is a calendar event, or an occurrence of a recurring one, to import as a session.
+// UID is the UID of the event, followed for an occurrence by a slash and its start in UTC, like
+// "meeting@example.com/20240102T090000Z", so that each occurrence is imported once.
+type ImportEvent struct {
+	UID       string    `json:"uid"`
+	Summary   string    `json:"summary"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+}
+
+// Outcomes of the import of an event. A new event is one that the preview would create. An
+// overlapping event is skipped because the REJECT overlap policy refuses it.
+const (
+	ImportStatusCreated  = "created"
+	ImportStatusNew      = "new"
+	ImportStatusImported = "already_imported"
+	ImportStatusFuture   = "in_future"
+	ImportStatusOverlap  = "overlaps"
+)
+
+// ImportedEvent is the outcome of the import of an event. TrackID is the created session. Overlaps
+// lists the sessions of the user the event overlaps, including ones imported before it.
+type ImportedEvent struct {
+	ImportEvent
+	Status   string      `json:"status"`
+	TrackID  *uuid.UUID  `json:"track_id,omitempty"`
+	Overlaps []uuid.UUID `json:"overlaps,omitempty"`
 }
 
+// ImportResult lists the outcome of the import of each event in the order of their start.
+// Created counts the sessions created, or that the preview would create.
+type ImportResult struct {
+	Preview bool            `json:"preview"`
+	Created int             `json:"created"`
+	Skipped int             `json:"skipped"`
+	Events  []ImportedEvent `json:"events"`
+}
+
+// SetNoteRequest replaces the note of the session ID.
+type SetNoteRequest struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Note string    `json:"note"`
+}
+
+type UpdateTrackRequest struct {
+	ID        uuid.UUID `json:"id" validate:"required"`
+	StartedAt time.Time `json:"started_at" validate:"required"`
+	EndedAt   time.Time `json:"ended_at" validate:"required"`
+}
+
+// SplitTrackRequest splits a session at the instant At. The part after At is moved to the task
+// TaskID when it is set.
+type SplitTrackRequest struct {
+	ID     uuid.UUID `json:"id" validate:"required"`
+	At     time.Time `json:"at" validate:"required"`
+	TaskID uuid.UUID `json:"task_id"`
+}
+
+type MergeTracksRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"min=2,dive,required"`
+}
+
+// TrackOverlap is a pair of sessions of the same user that were active at the same time.
+type TrackOverlap struct {
+	Track        TaskTrack `json:"track"`
+	OverlapsWith TaskTrack `json:"overlaps_with"`
+}
+
+// TrackFilter selects sessions. NoteFilter matches the sessions with notes containing it, in any case.
+// FromFilter and ToFilter select the sessions intersecting the period; a zero one leaves it open on
+// that side.
+type TrackFilter struct {
+	TaskIDFilter uuid.UUID
+	UserIDFilter uuid.UUID
+	NoteFilter   string
+	FromFilter   time.Time
+	ToFilter     time.Time
+}
+
+// CalcTimeRequest selects the sessions of the user ID, of the project ProjectID, of the client
+// ClientID, or of the user in the project or the client. GroupBy sums them per "task" (the
+// default), per "project" or per "client".
+// A session is tagged with the tags of its own and of its task. With Tags, only sessions with
+// any of them are summed, and sessions with any of ExcludeTags are left out. Status limits
+// the sessions to the tasks with that status. From and To limit the time to the period: sessions
+// crossing its boundaries are cut to their part inside. A zero From or To leaves it open on that side.
 type CalcTimeRequest struct {
-	ID uuid.UUID `json:"id"`
+	ID          uuid.UUID `json:"id" validate:"required_without_all=ProjectID ClientID"`
+	ProjectID   uuid.UUID `json:"project_id"`
+	ClientID    uuid.UUID `json:"client_id"`
+	GroupBy     string    `json:"group_by" validate:"omitempty,oneof=task project client"`
+	Tags        []string  `json:"tags"`
+	ExcludeTags []string  `json:"exclude_tags"`
+	Status      string    `json:"status" validate:"omitempty,oneof=todo in_progress done cancelled"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+}
+
+// Ways to group the time in the reports.
+const (
+	GroupByTask    = "task"
+	GroupByProject = "project"
+	GroupByClient  = "client"
+)
+
+// TimeTotal is the time tracked in the closed sessions of a task, a project or a client. For a
+// task, Total is Time together with the time of all of its subtasks. SessionsCount,
+// FirstStartedAt and LastEndedAt describe the own sessions; they are zero without them.
+type TimeTotal struct {
+	ID             uuid.UUID
+	Name           string
+	Time           time.Duration
+	Total          time.Duration
+	SessionsCount  int
+	FirstStartedAt time.Time
+	LastEndedAt    time.Time
+	PausesCount    int
+	PausedTime     time.Duration
+}
+
+// CalcTimeResult is a row of the calc-time report. The row is of a task, a project or a client, as
+// the report is grouped, and only the id and the name of that one are set. Own is the time
+// tracked on the task itself, Total includes its subtasks.
+type CalcTimeResult struct {
+	TaskID         *uuid.UUID `json:"task_id,omitempty"`
+	TaskName       string     `json:"task_name,omitempty"`
+	ProjectID      *uuid.UUID `json:"project_id,omitempty"`
+	ProjectName    string     `json:"project_name,omitempty"`
+	ClientID       *uuid.UUID `json:"client_id,omitempty"`
+	ClientName     string     `json:"client_name,omitempty"`
+	Total          Duration   `json:"total"`
+	Own            Duration   `json:"own"`
+	SessionsCount  int        `json:"sessions_count"`
+	FirstStartedAt *time.Time `json:"first_started_at"`
+	LastEndedAt    *time.Time `json:"last_ended_at"`
+	PausesCount    int        `json:"pauses_count"`
+	PausedTime     Duration   `json:"paused_time"`
 }
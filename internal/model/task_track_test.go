@@ -0,0 +1,190 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var day = time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+// at returns the instant of the test day at the hour and minute.
+func at(hour int, minute int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+// closedTrack returns a closed session from start to end with pauses given as pairs of instants.
+func closedTrack(start time.Time, end time.Time, pauses ...time.Time) TaskTrack {
+	track := TaskTrack{StartedAt: start, Base: Base{ID: uuid.New()}}
+	for i := 0; i+1 < len(pauses); i += 2 {
+		resumedAt := pauses[i+1]
+		track.Pauses = append(track.Pauses, TaskTrackPause{PausedAt: pauses[i], ResumedAt: &resumedAt})
+	}
+
+	track.Close(end)
+
+	return track
+}
+
+func TestActiveIntervals(t *testing.T) {
+	tests := []struct {
+		name  string
+		track TaskTrack
+		want  []Interval
+	}{
+		{"no pauses", closedTrack(at(9, 0), at(10, 0)),
+			[]Interval{{at(9, 0), at(10, 0)}}},
+		{"pause inside", closedTrack(at(9, 0), at(12, 0), at(10, 0), at(10, 30)),
+			[]Interval{{at(9, 0), at(10, 0)}, {at(10, 30), at(12, 0)}}},
+		{"pauses out of order", closedTrack(at(9, 0), at(12, 0), at(11, 0), at(11, 15), at(10, 0), at(10, 30)),
+			[]Interval{{at(9, 0), at(10, 0)}, {at(10, 30), at(11, 0)}, {at(11, 15), at(12, 0)}}},
+		{"pause at the start", closedTrack(at(9, 0), at(10, 0), at(9, 0), at(9, 20)),
+			[]Interval{{at(9, 20), at(10, 0)}}},
+		{"pause until the end", closedTrack(at(9, 0), at(10, 0), at(9, 40), at(10, 0)),
+			[]Interval{{at(9, 0), at(9, 40)}}},
+		{"crossing midnight", closedTrack(at(22, 0), at(26, 0), at(23, 30), at(24, 30)),
+			[]Interval{{at(22, 0), at(23, 30)}, {at(24, 30), at(26, 0)}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.track.ActiveIntervals()
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ActiveIntervals() = %v, want %v", got, test.want)
+			}
+
+			var active time.Duration
+			for _, interval := range got {
+				active += interval.End.Sub(interval.Start)
+			}
+
+			if active != *test.track.Time {
+				t.Errorf("active time %s, Time %s", active, *test.track.Time)
+			}
+		})
+	}
+}
+
+func TestSetPeriod(t *testing.T) {
+	tests := []struct {
+		name        string
+		start       time.Time
+		end         time.Time
+		time        time.Duration
+		pausesCount int
+		pausedTime  time.Duration
+	}{
+		{"same bounds", at(9, 0), at(12, 0), 2 * time.Hour, 2, time.Hour},
+		{"longer", at(8, 0), at(13, 0), 4 * time.Hour, 2, time.Hour},
+		{"pause cut at the start", at(9, 45), at(12, 0), 90 * time.Minute, 2, 45 * time.Minute},
+		{"pause cut at the end", at(9, 0), at(11, 15), 90 * time.Minute, 2, 45 * time.Minute},
+		{"pause dropped", at(9, 0), at(10, 0), 30 * time.Minute, 1, 30 * time.Minute},
+		{"no pause left", at(10, 30), at(11, 0), 30 * time.Minute, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			track := closedTrack(at(9, 0), at(12, 0), at(9, 30), at(10, 0), at(11, 0), at(11, 30))
+			track.SetPeriod(test.start, test.end)
+
+			if *track.Time != test.time {
+				t.Errorf("Time = %s, want %s", *track.Time, test.time)
+			}
+
+			if track.PausesCount != test.pausesCount {
+				t.Errorf("PausesCount = %d, want %d", track.PausesCount, test.pausesCount)
+			}
+
+			if track.PausedTime != test.pausedTime {
+				t.Errorf("PausedTime = %s, want %s", track.PausedTime, test.pausedTime)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		at     time.Time
+		first  time.Duration
+		second time.Duration
+	}{
+		{"before the pause", at(9, 15), 15 * time.Minute, 150 * time.Minute},
+		{"inside the pause", at(10, 15), 60 * time.Minute, 105 * time.Minute},
+		{"after the pause", at(11, 0), 90 * time.Minute, 75 * time.Minute},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			track := closedTrack(at(9, 0), at(12, 15), at(10, 0), at(10, 30))
+			total := *track.Time
+
+			secondID := uuid.New()
+			second := track.Split(test.at, secondID)
+
+			if *track.Time != test.first || *second.Time != test.second {
+				t.Errorf("parts of %s and %s, want %s and %s", *track.Time, *second.Time, test.first, test.second)
+			}
+
+			if *track.Time+*second.Time != total {
+				t.Errorf("parts add up to %s, want %s", *track.Time+*second.Time, total)
+			}
+
+			if !track.EndedAt.Equal(test.at) || !second.StartedAt.Equal(test.at) {
+				t.Errorf("parts meet at %s and %s, want %s", track.EndedAt, second.StartedAt, test.at)
+			}
+
+			if second.ID != secondID {
+				t.Errorf("second part has id %s, want %s", second.ID, secondID)
+			}
+
+			for _, pause := range second.Pauses {
+				if pause.TaskTrackID != secondID {
+					t.Errorf("pause of the second part belongs to %s", pause.TaskTrackID)
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name        string
+		next        TaskTrack
+		notes       [2]string
+		time        time.Duration
+		pausesCount int
+		note        string
+	}{
+		{"with a gap", closedTrack(at(11, 0), at(12, 0)), [2]string{"a", "b"}, 2 * time.Hour, 1, "a; b"},
+		{"adjacent", closedTrack(at(10, 0), at(11, 0)), [2]string{"", "b"}, 2 * time.Hour, 0, "b"},
+		{"with pauses", closedTrack(at(11, 0), at(13, 0), at(12, 0), at(12, 30)), [2]string{"a", "a"}, 150 * time.Minute, 2, "a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			track := closedTrack(at(9, 0), at(10, 0))
+			track.Note = test.notes[0]
+			test.next.Note = test.notes[1]
+
+			track.Merge(test.next)
+
+			if *track.Time != test.time {
+				t.Errorf("Time = %s, want %s", *track.Time, test.time)
+			}
+
+			if track.PausesCount != test.pausesCount {
+				t.Errorf("PausesCount = %d, want %d", track.PausesCount, test.pausesCount)
+			}
+
+			if track.Note != test.note {
+				t.Errorf("Note = %q, want %q", track.Note, test.note)
+			}
+
+			if !track.EndedAt.Equal(*test.next.EndedAt) {
+				t.Errorf("EndedAt = %s, want %s", track.EndedAt, test.next.EndedAt)
+			}
+		})
+	}
+}
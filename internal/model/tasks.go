@@ -1,11 +1,132 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 // Task is the db schema for the task table
 type Task struct {
-	Name   string `json:"name" db:"name"`
-	UserID uuid.UUID
-	User   User
+	Name      string `json:"name" db:"name"`
+	UserID    uuid.UUID
+	User      User
+	ProjectID *uuid.UUID     `json:"project_id" gorm:"index"`
+	Project   *Project       `json:"project,omitempty"`
+	ParentID  *uuid.UUID     `json:"parent_id" gorm:"index"`
+	Tags      []Tag          `json:"tags,omitempty" gorm:"many2many:task_tags"`
+	Status    string         `json:"status" gorm:"not null;default:todo"`
+	Estimate  *time.Duration `json:"estimate"`
+	Billable  bool           `json:"billable" gorm:"not null;default:false"`
 	Base
 }
+
+// Statuses of the task lifecycle.
+const (
+	TaskStatusTodo       = "todo"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusDone       = "done"
+	TaskStatusCancelled  = "cancelled"
+)
+
+// taskTransitions lists the statuses a task can move to from each status. Moving a done or
+// cancelled task back is reopening it.
+var taskTransitions = map[string][]string{
+	TaskStatusTodo:       {TaskStatusInProgress, TaskStatusDone, TaskStatusCancelled},
+	TaskStatusInProgress: {TaskStatusTodo, TaskStatusDone, TaskStatusCancelled},
+	TaskStatusDone:       {TaskStatusInProgress},
+	TaskStatusCancelled:  {TaskStatusTodo},
+}
+
+// CanMoveTo reports whether the task can change its status to status.
+func (t *Task) CanMoveTo(status string) bool {
+	for _, allowed := range taskTransitions[t.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsClosed reports whether the task is done or cancelled, so it can't be tracked until it is reopened.
+func (t *Task) IsClosed() bool {
+	return t.Status == TaskStatusDone || t.Status == TaskStatusCancelled
+}
+
+// AddTaskRequest creates a task. Estimate is a duration like "4h30m".
+type AddTaskRequest struct {
+	UserID    uuid.UUID  `json:"user_id" validate:"required"`
+	Name      string     `json:"name" validate:"required"`
+	ProjectID *uuid.UUID `json:"project_id"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	Estimate  string     `json:"estimate" example:"4h30m"`
+	Billable  bool       `json:"billable"`
+}
+
+// UpdateTaskRequest changes the fields of a task it holds, the fields left out keep their values.
+// The nil uuid removes the project or the parent task, an empty Estimate removes the estimate.
+type UpdateTaskRequest struct {
+	ID        uuid.UUID  `json:"id" validate:"required"`
+	Name      *string    `json:"name" validate:"omitempty,min=1"`
+	ProjectID *uuid.UUID `json:"project_id"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	Estimate  *string    `json:"estimate" example:"4h30m"`
+	Billable  *bool      `json:"billable"`
+}
+
+// TaskUpdate holds the changes of a task, nil fields are left as they are. The nil uuid removes the
+// project or the parent task, a zero Estimate removes the estimate.
+type TaskUpdate struct {
+	Name      *string
+	ProjectID *uuid.UUID
+	ParentID  *uuid.UUID
+	Estimate  *time.Duration
+	Billable  *bool
+}
+
+type UpdateTaskStatusRequest struct {
+	ID     uuid.UUID `json:"id" validate:"required"`
+	Status string    `json:"status" validate:"required,oneof=todo in_progress done cancelled"`
+}
+
+type DeleteTaskRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+type TaskFilter struct {
+	UserIDFilter    uuid.UUID
+	ProjectIDFilter uuid.UUID
+	ParentIDFilter  uuid.UUID
+	NameFilter      string
+	StatusFilter    string
+}
+
+// EstimateFilter selects the estimated tasks of the user UserIDFilter, of the project
+// ProjectIDFilter, or of the user in the project.
+type EstimateFilter struct {
+	UserIDFilter    uuid.UUID
+	ProjectIDFilter uuid.UUID
+}
+
+// TaskEstimate compares the estimate of a task with the time tracked on it and its subtasks.
+// Overrun is the time tracked over the estimate, OverrunPercent is it in percent of the estimate.
+type TaskEstimate struct {
+	TaskID         uuid.UUID     `json:"task_id"`
+	TaskName       string        `json:"task_name"`
+	Status         string        `json:"status"`
+	Estimate       time.Duration `json:"estimate"`
+	Tracked        time.Duration `json:"tracked"`
+	Remaining      time.Duration `json:"remaining"`
+	Overrun        time.Duration `json:"overrun"`
+	OverrunPercent float64       `json:"overrun_percent"`
+}
+
+// EstimateReport is the estimate-vs-actual report. OverBudget holds the tasks tracked over their
+// estimate, the most overrun first. Estimate, Tracked and Remaining are the sums over Tasks.
+type EstimateReport struct {
+	Tasks      []TaskEstimate `json:"tasks"`
+	OverBudget []TaskEstimate `json:"over_budget"`
+	Estimate   time.Duration  `json:"estimate"`
+	Tracked    time.Duration  `json:"tracked"`
+	Remaining  time.Duration  `json:"remaining"`
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TimesheetRequest selects the timesheet of the ISO week Week, like "2024-W12", of the user UserID,
+// of the project ProjectID, or of the user in the project. Days begin at midnight in Timezone, an
+// IANA name, UTC by default.
+type TimesheetRequest struct {
+	UserID    uuid.UUID `validate:"required_without=ProjectID"`
+	ProjectID uuid.UUID
+	Week      string `validate:"required"`
+	Timezone  string `validate:"omitempty,timezone"`
+}
+
+// UpdateTimesheetRequest sets the time of the tasks of the user UserID on the days of the ISO week
+// Week. Days holds the time of each day from Monday to Sunday, like "1h30m"; an empty day is left
+// as it is.
+type UpdateTimesheetRequest struct {
+	UserID   uuid.UUID             `json:"user_id" validate:"required"`
+	Week     string                `json:"week" validate:"required"`
+	Timezone string                `json:"timezone" validate:"omitempty,timezone"`
+	Rows     []TimesheetRowRequest `json:"rows" validate:"required,dive"`
+}
+
+// TimesheetRowRequest is the time of the task TaskID on each day of the week.
+type TimesheetRowRequest struct {
+	TaskID uuid.UUID `json:"task_id" validate:"required"`
+	Days   []string  `json:"days" validate:"len=7"`
+}
+
+// TimesheetCell is the time to have tracked on the task TaskID on the day Day of the week, 0 being
+// Monday.
+type TimesheetCell struct {
+	TaskID uuid.UUID
+	Day    int
+	Time   time.Duration
+}
+
+// TimesheetRow is the time tracked by a user on a task on each day of the week, Monday first.
+type TimesheetRow struct {
+	UserID      uuid.UUID
+	UserName    string
+	UserSurname string
+	TaskID      uuid.UUID
+	TaskName    string
+	Days        [7]time.Duration
+	Total       time.Duration
+}
+
+// Timesheet is the time tracked in the closed sessions of a week per user, task and day. Days are
+// the beginnings of the days, DayTotals the time of each day over all rows.
+type Timesheet struct {
+	Week      string
+	Days      [7]time.Time
+	Rows      []TimesheetRow
+	DayTotals [7]time.Duration
+	Total     time.Duration
+}
+
+// TimesheetRowResult is a row of the timesheet.
+type TimesheetRowResult struct {
+	UserID      uuid.UUID   `json:"user_id"`
+	UserName    string      `json:"user_name"`
+	UserSurname string      `json:"user_surname"`
+	TaskID      uuid.UUID   `json:"task_id"`
+	TaskName    string      `json:"task_name"`
+	Days        [7]Duration `json:"days"`
+	Total       Duration    `json:"total"`
+}
+
+// TimesheetResult is the timesheet of a week with the row, day and week totals.
+type TimesheetResult struct {
+	Week      string               `json:"week"`
+	Days      [7]time.Time         `json:"days"`
+	Rows      []TimesheetRowResult `json:"rows"`
+	DayTotals [7]Duration          `json:"day_totals"`
+	Total     Duration             `json:"total"`
+}
+
+// ParseISOWeek returns the beginning of the Monday of the ISO week, like "2024-W12", in location.
+func ParseISOWeek(week string, location *time.Location) (time.Time, error) {
+	var year, number int
+
+	_, err := fmt.Sscanf(week, "%4d-W%2d", &year, &number)
+	if err != nil || len(week) != len("2006-W01") {
+		return time.Time{}, fmt.Errorf("week %q is not like 2006-W01", week)
+	}
+
+	// January 4 is always in the first ISO week of its year.
+	monday := BucketStart(BucketWeek, time.Date(year, time.January, 4, 0, 0, 0, 0, location)).AddDate(0, 0, 7*(number-1))
+
+	if isoYear, isoWeek := monday.ISOWeek(); isoYear != year || isoWeek != number {
+		return time.Time{}, fmt.Errorf("year %d has no week %d", year, number)
+	}
+
+	return monday, nil
+}
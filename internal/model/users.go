@@ -1,6 +1,9 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 // User is the db schema for the user table
 type User struct {
@@ -8,6 +11,8 @@ type User struct {
 	Name       string `json:"name" db:"name"`
 	Address    string `json:"address" db:"address"`
 	Patronymic string `json:"patronymic" db:"patronymic"`
+	// CalendarTokenHash is the SHA-256 of the token of the calendar feed of the user, empty without one.
+	CalendarTokenHash string `json:"-" db:"calendar_token_hash" gorm:"index"`
 	Base
 }
 
@@ -33,3 +38,23 @@ type UserFilter struct {
 	AddressFilter    string
 	PatronymicFilter string
 }
+
+// CalendarTokenRequest asks for a new token of the calendar feed of the user ID. The new token
+// replaces the previous one, so the old feed URL stops working.
+type CalendarTokenRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// CalendarToken is the token of the calendar feed of a user and the path of the feed. The token is
+// shown once, only its hash is stored.
+type CalendarToken struct {
+	Token string `json:"token"`
+	Feed  string `json:"feed"`
+}
+
+// CalendarFilter selects the sessions of the calendar feed that intersect the period. A zero From or
+// To leaves it open on that side.
+type CalendarFilter struct {
+	From time.Time
+	To   time.Time
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 	_ "timeTracker/api/docs"
+	"timeTracker/api/templates"
 	"timeTracker/config"
 	"timeTracker/internal/handlers"
 	"timeTracker/internal/middlewares"
@@ -37,10 +38,13 @@ func (app *AppServer) Run(appConfig config.ApiEnvConfig) {
 	app.Sender = &httputils.Sender{
 		Render: render.New(render.Options{
 			IndentJSON: true,
+			Directory:  ".",
+			FileSystem: &render.EmbedFileSystem{FS: templates.FS},
+			Extensions: []string{".tmpl"},
 		}),
 	}
 
-	storage, err := storage.NewPostgresDB()
+	storage, err := storage.NewPostgresDB(appConfig)
 	if err != nil {
 		logger.Log.Error(err)
 		panic(err.Error())
@@ -59,9 +63,58 @@ func (app *AppServer) Run(appConfig config.ApiEnvConfig) {
 	router.Methods("POST").Path("/api/users").HandlerFunc(app.AddUser)
 	router.Methods("PUT").Path("/api/users").HandlerFunc(app.UpdateUser)
 	router.Methods("DELETE").Path("/api/users").HandlerFunc(app.DeleteUser)
+	router.Methods("POST").Path("/api/users/calendar-token").HandlerFunc(app.ResetCalendarToken)
+	router.Methods("GET").Path("/api/calendar/{token}.ics").HandlerFunc(app.GetCalendar)
+	router.Methods("GET").Path("/api/tasks").HandlerFunc(app.GetTasks)
+	router.Methods("GET").Path("/api/tasks/{id}").HandlerFunc(app.GetTask)
+	router.Methods("POST").Path("/api/tasks").HandlerFunc(app.AddTask)
+	router.Methods("PUT").Path("/api/tasks").HandlerFunc(app.UpdateTask)
+	router.Methods("PUT").Path("/api/tasks/status").HandlerFunc(app.UpdateTaskStatus)
+	router.Methods("DELETE").Path("/api/tasks").HandlerFunc(app.DeleteTask)
+	router.Methods("GET").Path("/api/estimates").HandlerFunc(app.GetEstimates)
+	router.Methods("GET").Path("/api/projects").HandlerFunc(app.GetProjects)
+	router.Methods("GET").Path("/api/projects/{id}").HandlerFunc(app.GetProject)
+	router.Methods("POST").Path("/api/projects").HandlerFunc(app.AddProject)
+	router.Methods("PUT").Path("/api/projects").HandlerFunc(app.UpdateProject)
+	router.Methods("DELETE").Path("/api/projects").HandlerFunc(app.DeleteProject)
+	router.Methods("GET").Path("/api/clients").HandlerFunc(app.GetClients)
+	router.Methods("GET").Path("/api/clients/{id}").HandlerFunc(app.GetClient)
+	router.Methods("POST").Path("/api/clients").HandlerFunc(app.AddClient)
+	router.Methods("PUT").Path("/api/clients").HandlerFunc(app.UpdateClient)
+	router.Methods("DELETE").Path("/api/clients").HandlerFunc(app.DeleteClient)
+	router.Methods("POST").Path("/api/clients/report").HandlerFunc(app.GetClientReport)
+	router.Methods("GET").Path("/api/invoices").HandlerFunc(app.GetInvoices)
+	router.Methods("GET").Path("/api/invoices/{id}").HandlerFunc(app.GetInvoice)
+	router.Methods("POST").Path("/api/invoices").HandlerFunc(app.AddInvoice)
+	router.Methods("PUT").Path("/api/invoices/status").HandlerFunc(app.UpdateInvoiceStatus)
+	router.Methods("GET").Path("/api/tags").HandlerFunc(app.GetTags)
+	router.Methods("POST").Path("/api/tags").HandlerFunc(app.AddTag)
+	router.Methods("PUT").Path("/api/tags").HandlerFunc(app.UpdateTag)
+	router.Methods("DELETE").Path("/api/tags").HandlerFunc(app.DeleteTag)
+	router.Methods("PUT").Path("/api/tasks/tags").HandlerFunc(app.SetTaskTags)
+	router.Methods("PUT").Path("/api/tracks/tags").HandlerFunc(app.SetTrackTags)
+	router.Methods("PUT").Path("/api/tracks/billable").HandlerFunc(app.SetTrackBillable)
+	router.Methods("PUT").Path("/api/tracks/note").HandlerFunc(app.SetTrackNote)
 	router.Methods("POST").Path("/api/start-track").HandlerFunc(app.StartTrackTask)
 	router.Methods("POST").Path("/api/end-track").HandlerFunc(app.EndTrackTask)
+	router.Methods("POST").Path("/api/pause-track").HandlerFunc(app.PauseTrackTask)
+	router.Methods("POST").Path("/api/resume-track").HandlerFunc(app.ResumeTrackTask)
+	router.Methods("GET").Path("/api/tracks").HandlerFunc(app.GetTracks)
+	router.Methods("POST").Path("/api/tracks").HandlerFunc(app.AddTrack)
+	router.Methods("POST").Path("/api/tracks/import").HandlerFunc(app.ImportTracks)
+	router.Methods("PUT").Path("/api/tracks").HandlerFunc(app.UpdateTrack)
+	router.Methods("POST").Path("/api/tracks/split").HandlerFunc(app.SplitTrack)
+	router.Methods("POST").Path("/api/tracks/merge").HandlerFunc(app.MergeTracks)
+	router.Methods("GET").Path("/api/tracks/{id}/revisions").HandlerFunc(app.GetTrackRevisions)
+	router.Methods("GET").Path("/api/overlaps").HandlerFunc(app.GetOverlaps)
 	router.Methods("POST").Path("/api/calc-time").HandlerFunc(app.CalcTime)
+	router.Methods("POST").Path("/api/calc-time/buckets").HandlerFunc(app.GetTimeBuckets)
+	router.Methods("POST").Path("/api/calc-time/team").HandlerFunc(app.GetTeamReport)
+	router.Methods("GET").Path("/api/timesheet").HandlerFunc(app.GetTimesheet)
+	router.Methods("PUT").Path("/api/timesheet").HandlerFunc(app.UpdateTimesheet)
+	router.Methods("GET").Path("/api/rates").HandlerFunc(app.GetRates)
+	router.Methods("POST").Path("/api/rates").HandlerFunc(app.AddRate)
+	router.Methods("POST").Path("/api/earnings").HandlerFunc(app.GetEarnings)
 
 	if app.Env != config.PROD_ENV {
 		router.Methods("GET").PathPrefix("/api/docs/").Handler(httpSwagger.Handler(
@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+func (s *Storage) GetClients(ctx context.Context, filters model.ClientFilter, pagination utils.Pagination) ([]model.Client, error) {
+	var clients []model.Client
+
+	query := s.db.Model(&model.Client{}).Where(&model.Client{Name: filters.NameFilter})
+
+	err := query.Scopes(utils.Paginate(clients, &pagination, query.Session(&gorm.Session{}))).Find(&clients).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func (s *Storage) GetClient(ctx context.Context, clientID uuid.UUID) (model.Client, error) {
+	var client model.Client
+	err := s.db.Where("id = ?", clientID).First(&client).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Client{}, localErr.NotFound("no client with that id")
+	}
+
+	if err != nil {
+		return model.Client{}, err
+	}
+
+	return client, nil
+}
+
+// AddClient creates the client. Its default rate starts now.
+func (s *Storage) AddClient(ctx context.Context, client model.Client) (model.Client, error) {
+	if client.DefaultRate != nil && client.DefaultRate.IsNegative() {
+		return model.Client{}, localErr.Invalid("default rate can't be negative")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&client).Error
+		if err != nil {
+			return err
+		}
+
+		return addClientRate(tx, client)
+	})
+
+	if err != nil {
+		return model.Client{}, err
+	}
+
+	return client, nil
+}
+
+// UpdateClient changes the client. A changed default rate starts now, the work done before keeps
+// the old one. The default rate can be changed, but not removed.
+func (s *Storage) UpdateClient(ctx context.Context, client model.Client) (model.Client, error) {
+	savedClient, err := s.GetClient(ctx, client.ID)
+	if err != nil {
+		return model.Client{}, err
+	}
+
+	if savedClient.DefaultRate != nil && client.DefaultRate == nil {
+		return model.Client{}, localErr.Invalid("default rate can't be removed")
+	}
+
+	if client.DefaultRate != nil && client.DefaultRate.IsNegative() {
+		return model.Client{}, localErr.Invalid("default rate can't be negative")
+	}
+
+	rateChanged := client.DefaultRate != nil && (savedClient.DefaultRate == nil || !savedClient.DefaultRate.Equal(*client.DefaultRate))
+
+	savedClient.Name = client.Name
+	savedClient.Contact = client.Contact
+	savedClient.Currency = client.Currency
+	savedClient.DefaultRate = client.DefaultRate
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(&savedClient).Error
+		if err != nil {
+			return err
+		}
+
+		if !rateChanged {
+			return nil
+		}
+
+		return addClientRate(tx, savedClient)
+	})
+
+	if err != nil {
+		return model.Client{}, err
+	}
+
+	return savedClient, nil
+}
+
+// DeleteClient deletes the client. Its projects are kept without a client, so a client with billed
+// sessions can't be deleted.
+func (s *Storage) DeleteClient(ctx context.Context, clientID uuid.UUID) (bool, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", clientID).Delete(&model.Client{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return localErr.NotFound("no client with that id")
+		}
+
+		err := checkClientNotBilled(tx, clientID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Project{}).Where("client_id = ?", clientID).Update("client_id", nil).Error
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetClientReport returns the time tracked for each client in the period with the amount of its
+// billable part. The time is summed by CalcTime, so it agrees with the calc-time report, and
+// sessions crossing the boundaries of the period count with their part inside it in both.
+func (s *Storage) GetClientReport(ctx context.Context, request model.ClientReportRequest) ([]model.ClientTotal, error) {
+	var clients []model.Client
+	query := s.db.Order("name")
+
+	if request.ClientID != uuid.Nil {
+		query = query.Where("id = ?", request.ClientID)
+	}
+
+	err := query.Find(&clients).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if request.ClientID != uuid.Nil && len(clients) == 0 {
+		return nil, localErr.NotFound("no client with that id")
+	}
+
+	totals, err := s.CalcTime(ctx, model.CalcTimeRequest{
+		ClientID: request.ClientID,
+		GroupBy:  model.GroupByClient,
+		From:     request.From,
+		To:       request.To,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[uuid.UUID]time.Duration, len(totals))
+	for _, total := range totals {
+		tracked[total.ID] = total.Time
+	}
+
+	// The billable sessions are selected and cut to the period as CalcTime does, so that the
+	// billable time is a part of the time. Rates apply by the start of the whole session.
+	columns := periodColumns(request.From, request.To)
+	query = calcTimeTracks(s.db, model.CalcTimeRequest{ClientID: request.ClientID, From: request.From, To: request.To}).
+		Joins("JOIN clients ON clients.id = projects.client_id").
+		Select(fmt.Sprintf("task_tracks.id, task_tracks.task_id, tasks.name AS task_name, tasks.user_id, tasks.project_id, "+
+			"projects.client_id, task_tracks.started_at, (%s)::bigint AS time, task_tracks.note", columns.time), columns.vars()...).
+		Where("task_tracks.billable")
+
+	var sessions []billableSession
+	err = query.Scan(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := loadRates(s.db, sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]model.ClientTotal, 0, len(clients))
+	index := make(map[uuid.UUID]int, len(clients))
+	for i, client := range clients {
+		index[client.ID] = i
+		report = append(report, model.ClientTotal{
+			ClientID: client.ID,
+			Name:     client.Name,
+			Currency: client.Currency,
+			Time:     tracked[client.ID],
+		})
+	}
+
+	for _, session := range sessions {
+		i, ok := index[*session.ClientID]
+		if !ok {
+			continue
+		}
+
+		report[i].BillableTime += session.Time
+
+		rate, ok := rates.at(session)
+		if !ok {
+			report[i].Unrated += session.Time
+			continue
+		}
+
+		report[i].Amount = report[i].Amount.Add(sessionAmount(rate, session.Time))
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Time > report[j].Time
+	})
+
+	return report, nil
+}
+
+// addClientRate keeps the default rate of the client as its rate starting now.
+func addClientRate(tx *gorm.DB, client model.Client) error {
+	if client.DefaultRate == nil {
+		return nil
+	}
+
+	return tx.Create(&model.Rate{
+		ClientID: &client.ID,
+		Amount:   *client.DefaultRate,
+		StartsAt: time.Now(),
+		Base:     model.Base{ID: uuid.New()},
+	}).Error
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"math"
+	"sort"
+	"timeTracker/internal/model"
+)
+
+// GetEstimates compares the estimated tasks of the filter with the time tracked on them. The time
+// of a task includes its subtasks, as in the calc-time report.
+func (s *Storage) GetEstimates(ctx context.Context, filters model.EstimateFilter) (model.EstimateReport, error) {
+	var tasks []model.Task
+	query := s.db.Where("estimate IS NOT NULL")
+
+	if filters.UserIDFilter != uuid.Nil {
+		query = query.Where("user_id = ?", filters.UserIDFilter)
+	}
+
+	if filters.ProjectIDFilter != uuid.Nil {
+		query = query.Where("project_id = ?", filters.ProjectIDFilter)
+	}
+
+	err := query.Order("name").Find(&tasks).Error
+	if err != nil {
+		return model.EstimateReport{}, err
+	}
+
+	totals, err := s.CalcTime(ctx, model.CalcTimeRequest{ID: filters.UserIDFilter, ProjectID: filters.ProjectIDFilter})
+	if err != nil {
+		return model.EstimateReport{}, err
+	}
+
+	tracked := make(map[uuid.UUID]model.TimeTotal, len(totals))
+	for _, total := range totals {
+		tracked[total.ID] = total
+	}
+
+	report := model.EstimateReport{Tasks: []model.TaskEstimate{}, OverBudget: []model.TaskEstimate{}}
+	for _, task := range tasks {
+		estimate := model.TaskEstimate{
+			TaskID:   task.ID,
+			TaskName: task.Name,
+			Status:   task.Status,
+			Estimate: *task.Estimate,
+			Tracked:  tracked[task.ID].Total,
+		}
+
+		if estimate.Tracked > estimate.Estimate {
+			estimate.Overrun = estimate.Tracked - estimate.Estimate
+			estimate.OverrunPercent = math.Round(float64(estimate.Overrun)/float64(estimate.Estimate)*1000) / 10
+			report.OverBudget = append(report.OverBudget, estimate)
+		} else {
+			estimate.Remaining = estimate.Estimate - estimate.Tracked
+		}
+
+		report.Tasks = append(report.Tasks, estimate)
+		report.Estimate += estimate.Estimate
+		report.Tracked += estimate.Tracked
+		report.Remaining += estimate.Remaining
+	}
+
+	sort.SliceStable(report.OverBudget, func(i, j int) bool {
+		return report.OverBudget[i].OverrunPercent > report.OverBudget[j].OverrunPercent
+	})
+
+	return report, nil
+}
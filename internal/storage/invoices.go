@@ -0,0 +1,317 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"strings"
+	"time"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+func (s *Storage) GetInvoices(ctx context.Context, filters model.InvoiceFilter, pagination utils.Pagination) ([]model.Invoice, error) {
+	var invoices []model.Invoice
+
+	query := s.db.Model(&model.Invoice{}).Where(&model.Invoice{ClientID: filters.ClientIDFilter, Status: filters.StatusFilter})
+
+	err := query.Scopes(utils.Paginate(invoices, &pagination, query.Session(&gorm.Session{}))).Find(&invoices).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// GetInvoice returns the invoice with its client and lines.
+func (s *Storage) GetInvoice(ctx context.Context, invoiceID uuid.UUID) (model.Invoice, error) {
+	var invoice model.Invoice
+	err := s.db.Where("id = ?", invoiceID).Preload("Client", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("description")
+	}).First(&invoice).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Invoice{}, localErr.NotFound("no invoice with that id")
+	}
+
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	return invoice, nil
+}
+
+// AddInvoice creates a draft invoice of the unbilled billable sessions of the client in the period
+// and marks them as billed. Every session needs a rate, so the invoice bills all of its time.
+func (s *Storage) AddInvoice(ctx context.Context, request model.AddInvoiceRequest) (model.Invoice, error) {
+	client, err := s.GetClient(ctx, request.ClientID)
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	if request.GroupBy == "" {
+		request.GroupBy = model.GroupByProject
+	}
+
+	invoice := model.Invoice{
+		ClientID:   client.ID,
+		PeriodFrom: request.From,
+		PeriodTo:   request.To,
+		GroupBy:    request.GroupBy,
+		Status:     model.InvoiceStatusDraft,
+		Currency:   client.Currency,
+		Base:       model.Base{ID: uuid.New()},
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Invoices are created one at a time, so the numbers are sequential and a session can't
+		// get on two invoices.
+		err := tx.Exec("LOCK TABLE invoices IN SHARE ROW EXCLUSIVE MODE").Error
+		if err != nil {
+			return err
+		}
+
+		var sessions []billableSession
+		err = billableSessions(tx, request.From, request.To).
+			Where("projects.client_id = ? AND task_tracks.invoice_id IS NULL", client.ID).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "task_tracks"}}).
+			Scan(&sessions).Error
+
+		if err != nil {
+			return err
+		}
+
+		if len(sessions) == 0 {
+			return localErr.Invalid("no unbilled billable time of the client in the period")
+		}
+
+		rates, err := loadRates(tx, sessions)
+		if err != nil {
+			return err
+		}
+
+		var unrated []uuid.UUID
+		lines := make(map[uuid.UUID]*model.InvoiceLine)
+		notes := make(map[uuid.UUID]map[string]bool)
+		sessionIDs := make([]uuid.UUID, 0, len(sessions))
+		for _, session := range sessions {
+			rate, ok := rates.at(session)
+			if !ok {
+				unrated = append(unrated, session.ID)
+				continue
+			}
+
+			lineID := *session.ProjectID
+			if request.GroupBy == model.GroupByTask {
+				lineID = session.TaskID
+			}
+
+			line, ok := lines[lineID]
+			if !ok {
+				line = &model.InvoiceLine{InvoiceID: invoice.ID, ProjectID: session.ProjectID, Base: model.Base{ID: uuid.New()}}
+				if request.GroupBy == model.GroupByTask {
+					line.TaskID = &session.TaskID
+					line.Description = session.TaskName
+				}
+				lines[lineID] = line
+			}
+
+			if session.Note != "" && !notes[lineID][session.Note] {
+				if notes[lineID] == nil {
+					notes[lineID] = make(map[string]bool)
+				}
+				notes[lineID][session.Note] = true
+				line.Notes = strings.TrimPrefix(line.Notes+"; "+session.Note, "; ")
+			}
+
+			amount := sessionAmount(rate, session.Time)
+			line.Time += session.Time
+			line.Amount = line.Amount.Add(amount)
+			invoice.Time += session.Time
+			invoice.Total = invoice.Total.Add(amount)
+			sessionIDs = append(sessionIDs, session.ID)
+		}
+
+		if len(unrated) > 0 {
+			return localErr.Conflict("billable sessions without a rate", unrated)
+		}
+
+		if request.GroupBy == model.GroupByProject {
+			err = describeProjectLines(tx, lines)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, line := range lines {
+			invoice.Lines = append(invoice.Lines, *line)
+		}
+
+		sort.Slice(invoice.Lines, func(i, j int) bool {
+			return invoice.Lines[i].Description < invoice.Lines[j].Description
+		})
+
+		err = tx.Unscoped().Model(&model.Invoice{}).Select("COALESCE(MAX(number), 0) + 1").Scan(&invoice.Number).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Omit("Client").Create(&invoice).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.TaskTrack{}).Where("id IN ?", sessionIDs).Update("invoice_id", invoice.ID).Error
+	})
+
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	invoice.Client = &client
+
+	return invoice, nil
+}
+
+// UpdateInvoiceStatus moves the invoice to the status, if the lifecycle allows it. Voiding an
+// invoice releases its sessions, so they can be edited and billed again.
+func (s *Storage) UpdateInvoiceStatus(ctx context.Context, invoiceID uuid.UUID, status string) (model.Invoice, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var invoice model.Invoice
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", invoiceID).First(&invoice).Error
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return localErr.NotFound("no invoice with that id")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !invoice.CanMoveTo(status) {
+			return localErr.Invalid(fmt.Sprintf("invoice can't move from %s to %s", invoice.Status, status))
+		}
+
+		updates := map[string]interface{}{"status": status}
+		switch status {
+		case model.InvoiceStatusIssued:
+			updates["issued_at"] = time.Now()
+		case model.InvoiceStatusPaid:
+			updates["paid_at"] = time.Now()
+		case model.InvoiceStatusVoid:
+			err = tx.Model(&model.TaskTrack{}).Where("invoice_id = ?", invoice.ID).Update("invoice_id", nil).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&invoice).Updates(updates).Error
+	})
+
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	return s.GetInvoice(ctx, invoiceID)
+}
+
+// describeProjectLines names the lines of an invoice grouped by project after their projects.
+func describeProjectLines(tx *gorm.DB, lines map[uuid.UUID]*model.InvoiceLine) error {
+	projectIDs := make([]uuid.UUID, 0, len(lines))
+	for projectID := range lines {
+		projectIDs = append(projectIDs, projectID)
+	}
+
+	var projects []model.Project
+	err := tx.Where("id IN ?", projectIDs).Find(&projects).Error
+	if err != nil {
+		return err
+	}
+
+	for _, project := range projects {
+		lines[project.ID].Description = project.Name
+	}
+
+	return nil
+}
+
+// checkNotBilled refuses changes of a session billed on an invoice.
+func checkNotBilled(track *model.TaskTrack) error {
+	if track.InvoiceID != nil {
+		return localErr.Conflict("session is billed on an invoice", track.InvoiceID)
+	}
+
+	return nil
+}
+
+// checkTaskNotBilled refuses to move the task to another project, or client, while sessions of
+// the task are billed on an invoice.
+func checkTaskNotBilled(tx *gorm.DB, taskID uuid.UUID) error {
+	var billed int64
+	err := tx.Model(&model.TaskTrack{}).Where("task_id = ? AND invoice_id IS NOT NULL", taskID).Count(&billed).Error
+	if err != nil {
+		return err
+	}
+
+	if billed > 0 {
+		return localErr.Conflict("task has sessions billed on an invoice", nil)
+	}
+
+	return nil
+}
+
+// checkProjectNotBilled refuses to move the project to another client while sessions of its tasks
+// are billed on an invoice.
+func checkProjectNotBilled(tx *gorm.DB, projectID uuid.UUID) error {
+	var billed int64
+	err := tx.Model(&model.TaskTrack{}).
+		Joins("JOIN tasks ON tasks.id = task_tracks.task_id").
+		Where("tasks.project_id = ? AND task_tracks.invoice_id IS NOT NULL", projectID).
+		Count(&billed).Error
+	if err != nil {
+		return err
+	}
+
+	if billed > 0 {
+		return localErr.Conflict("project has sessions billed on an invoice", nil)
+	}
+
+	return nil
+}
+
+// checkClientNotBilled refuses to detach the projects of the client while sessions of their tasks
+// are billed on an invoice.
+func checkClientNotBilled(tx *gorm.DB, clientID uuid.UUID) error {
+	var billed int64
+	err := tx.Model(&model.TaskTrack{}).
+		Joins("JOIN tasks ON tasks.id = task_tracks.task_id").
+		Joins("JOIN projects ON projects.id = tasks.project_id").
+		Where("projects.client_id = ? AND task_tracks.invoice_id IS NOT NULL", clientID).
+		Count(&billed).Error
+	if err != nil {
+		return err
+	}
+
+	if billed > 0 {
+		return localErr.Conflict("client has sessions billed on an invoice", nil)
+	}
+
+	return nil
+}
+
+// sameID reports whether the optional ids are both unset or equal.
+func sameID(a *uuid.UUID, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+	"timeTracker/config"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+)
+
+// GetOverlaps returns every pair of closed sessions of the user that were active at the same time
+// within [from, to]. A zero from or to leaves the period open on that side.
+func (s *Storage) GetOverlaps(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time) ([]model.TrackOverlap, error) {
+	tracks, err := userClosedTracks(s.db, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	overlaps := []model.TrackOverlap{}
+	for i := range tracks {
+		for j := i + 1; j < len(tracks); j++ {
+			if tracks[i].Overlaps(&tracks[j]) {
+				overlaps = append(overlaps, model.TrackOverlap{Track: tracks[i], OverlapsWith: tracks[j]})
+			}
+		}
+	}
+
+	return overlaps, nil
+}
+
+// rejectOverlaps refuses the closed session with the REJECT overlap policy, when it overlaps other
+// sessions of the same user. The conflicting sessions are sent in the error data.
+func (s *Storage) rejectOverlaps(tx *gorm.DB, track *model.TaskTrack) error {
+	if s.config.OverlapPolicy != config.OVERLAP_POLICY_REJECT || track.EndedAt == nil {
+		return nil
+	}
+
+	userID, err := trackOwner(tx, track)
+	if err != nil {
+		return err
+	}
+
+	overlaps, err := overlappingTracks(tx, userID, track)
+	if err != nil {
+		return err
+	}
+
+	if len(overlaps) > 0 {
+		return localErr.Conflict("time entry overlaps other sessions", overlaps)
+	}
+
+	return nil
+}
+
+// overlappingTracks returns the other closed sessions of the user that overlap the closed session.
+func overlappingTracks(tx *gorm.DB, userID uuid.UUID, track *model.TaskTrack) ([]model.TaskTrack, error) {
+	candidates, err := userClosedTracks(tx, userID, track.StartedAt, *track.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlaps []model.TaskTrack
+	for _, candidate := range candidates {
+		if candidate.ID != track.ID && track.Overlaps(&candidate) {
+			overlaps = append(overlaps, candidate)
+		}
+	}
+
+	return overlaps, nil
+}
+
+// refreshOverlaps recalculates the overlapping flag of the closed sessions of the user within [from, to].
+func refreshOverlaps(tx *gorm.DB, userID uuid.UUID, from time.Time, to time.Time) error {
+	tracks, err := userClosedTracks(tx, userID, from, to)
+	if err != nil || len(tracks) == 0 {
+		return err
+	}
+
+	// Sessions in the period can overlap ones that start before or end after it.
+	for _, track := range tracks {
+		if track.StartedAt.Before(from) {
+			from = track.StartedAt
+		}
+
+		if track.EndedAt.After(to) {
+			to = *track.EndedAt
+		}
+	}
+
+	candidates, err := userClosedTracks(tx, userID, from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, track := range tracks {
+		overlapping := false
+		for _, candidate := range candidates {
+			if candidate.ID != track.ID && track.Overlaps(&candidate) {
+				overlapping = true
+				break
+			}
+		}
+
+		if overlapping != track.Overlapping {
+			err = tx.Model(&model.TaskTrack{}).Where("id = ?", track.ID).Update("overlapping", overlapping).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// userClosedTracks loads the closed sessions of the user that intersect [from, to] together with their pauses.
+// Sessions of deleted tasks are left out. A zero from or to leaves the period open on that side.
+func userClosedTracks(tx *gorm.DB, userID uuid.UUID, from time.Time, to time.Time) ([]model.TaskTrack, error) {
+	var tracks []model.TaskTrack
+
+	query := tx.Joins("JOIN tasks ON tasks.id = task_tracks.task_id AND tasks.deleted_at IS NULL").
+		Where("tasks.user_id = ? AND task_tracks.ended_at IS NOT NULL", userID)
+
+	if !from.IsZero() {
+		query = query.Where("task_tracks.ended_at > ?", from)
+	}
+
+	if !to.IsZero() {
+		query = query.Where("task_tracks.started_at < ?", to)
+	}
+
+	err := query.Order("task_tracks.started_at").Preload("Pauses").Find(&tracks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
+}
+
+// trackOwner returns the user of the task of the session.
+func trackOwner(tx *gorm.DB, track *model.TaskTrack) (uuid.UUID, error) {
+	var task model.Task
+
+	err := tx.Unscoped().Select("user_id").Where("id = ?", track.TaskID).First(&task).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return task.UserID, nil
+}
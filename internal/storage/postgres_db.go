@@ -2,15 +2,16 @@ package storage
 
 import (
 	"os"
+	"timeTracker/config"
 	"timeTracker/internal/model"
+	"timeTracker/pkg/logger"
 
 	_ "github.com/golang-migrate/migrate/source/file" // import file driver for migrate
-	"github.com/sash20m/go-api-template/pkg/logger"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func NewPostgresDB() (*Storage, error) {
+func NewPostgresDB(appConfig config.ApiEnvConfig) (*Storage, error) {
 	dsn := "host=" + os.Getenv("DB_HOST") + " user=" + os.Getenv("DB_USER") + " dbname=" + os.Getenv("DB_NAME") + " password=" + os.Getenv("DB_PASSWORD") + " sslmode=disable"
 
 	logger.Log.Info(dsn, " eee")
@@ -20,7 +21,7 @@ func NewPostgresDB() (*Storage, error) {
 		return &Storage{}, err
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, config: appConfig}, nil
 }
 
 // MigratePostgres migrates the postgres db to a new version.
@@ -31,6 +32,21 @@ func (s *Storage) MigratePostgres() error {
 		return err
 	}
 
+	err = s.db.AutoMigrate(&model.Tag{})
+	if err != nil {
+		return err
+	}
+
+	err = s.db.AutoMigrate(&model.Client{})
+	if err != nil {
+		return err
+	}
+
+	err = s.db.AutoMigrate(&model.Project{})
+	if err != nil {
+		return err
+	}
+
 	err = s.db.AutoMigrate(&model.Task{})
 	if err != nil {
 		return err
@@ -41,6 +57,37 @@ func (s *Storage) MigratePostgres() error {
 		return err
 	}
 
+	err = s.db.AutoMigrate(&model.TaskTrackPause{})
+	if err != nil {
+		return err
+	}
+
+	err = s.db.AutoMigrate(&model.TaskTrackRevision{})
+	if err != nil {
+		return err
+	}
+
+	err = s.db.AutoMigrate(&model.Rate{})
+	if err != nil {
+		return err
+	}
+
+	err = s.db.AutoMigrate(&model.Invoice{}, &model.InvoiceLine{})
+	if err != nil {
+		return err
+	}
+
+	// Sessions tracked before started_at and ended_at existed only had created_at and time.
+	err = s.db.Exec("UPDATE task_tracks SET started_at = created_at WHERE started_at IS NULL").Error
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Exec("UPDATE task_tracks SET ended_at = started_at + (time / 1000) * interval '1 microsecond' WHERE ended_at IS NULL AND time IS NOT NULL").Error
+	if err != nil {
+		return err
+	}
+
 	logger.Log.Info("Migrations script ran successfully")
 
 	return nil
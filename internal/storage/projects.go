@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+func (s *Storage) GetProjects(ctx context.Context, filters model.ProjectFilter, pagination utils.Pagination) ([]model.Project, error) {
+	var projects []model.Project
+
+	query := s.db.Model(&model.Project{}).Where(&model.Project{Name: filters.NameFilter})
+
+	if filters.ClientIDFilter != uuid.Nil {
+		query = query.Where("client_id = ?", filters.ClientIDFilter)
+	}
+
+	err := query.Scopes(utils.Paginate(projects, &pagination, query.Session(&gorm.Session{}))).Find(&projects).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (s *Storage) GetProject(ctx context.Context, projectID uuid.UUID) (model.Project, error) {
+	var project model.Project
+	err := s.db.Where("id = ?", projectID).First(&project).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Project{}, localErr.NotFound("no project with that id")
+	}
+
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	return project, nil
+}
+
+func (s *Storage) AddProject(ctx context.Context, project model.Project) (model.Project, error) {
+	if project.ClientID != nil {
+		if _, err := s.GetClient(ctx, *project.ClientID); err != nil {
+			return model.Project{}, err
+		}
+	}
+
+	err := s.db.Omit(clause.Associations).Create(&project).Error
+
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	return project, nil
+}
+
+func (s *Storage) UpdateProject(ctx context.Context, project model.Project) (model.Project, error) {
+	savedProject, err := s.GetProject(ctx, project.ID)
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	if project.ClientID != nil {
+		if _, err := s.GetClient(ctx, *project.ClientID); err != nil {
+			return model.Project{}, err
+		}
+	}
+
+	if !sameID(savedProject.ClientID, project.ClientID) {
+		err = checkProjectNotBilled(s.db, project.ID)
+		if err != nil {
+			return model.Project{}, err
+		}
+	}
+
+	savedProject.Name = project.Name
+	savedProject.Description = project.Description
+	savedProject.ClientID = project.ClientID
+
+	err = s.db.Omit(clause.Associations).Save(&savedProject).Error
+
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	return savedProject, nil
+}
+
+// DeleteProject deletes the project. Its tasks are kept without a project, so a project with billed
+// sessions can't be deleted.
+func (s *Storage) DeleteProject(ctx context.Context, projectID uuid.UUID) (bool, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", projectID).Delete(&model.Project{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return localErr.NotFound("no project with that id")
+		}
+
+		err := checkProjectNotBilled(tx, projectID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Task{}).Where("project_id = ?", projectID).Update("project_id", nil).Error
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
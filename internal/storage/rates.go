@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+)
+
+// GetRates returns the rates of the filter, the latest first.
+func (s *Storage) GetRates(ctx context.Context, filters model.RateFilter) ([]model.Rate, error) {
+	var rates []model.Rate
+	query := s.db.Order("starts_at desc")
+
+	if filters.UserIDFilter != uuid.Nil {
+		query = query.Where("user_id = ?", filters.UserIDFilter)
+	}
+
+	if filters.ClientIDFilter != uuid.Nil {
+		query = query.Where("client_id = ?", filters.ClientIDFilter)
+	}
+
+	if filters.ProjectIDFilter != uuid.Nil {
+		query = query.Where("project_id = ?", filters.ProjectIDFilter)
+	}
+
+	if filters.TaskIDFilter != uuid.Nil {
+		query = query.Where("task_id = ?", filters.TaskIDFilter)
+	}
+
+	err := query.Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// AddRate sets a new rate for its user, project or task. A rate can't start in the past, so the
+// amounts of the work already done never change.
+func (s *Storage) AddRate(ctx context.Context, rate model.Rate) (model.Rate, error) {
+	if rate.Amount.IsNegative() {
+		return model.Rate{}, localErr.Invalid("amount can't be negative")
+	}
+
+	now := time.Now()
+	if rate.StartsAt.IsZero() {
+		rate.StartsAt = now
+	}
+
+	if rate.StartsAt.Before(now) {
+		return model.Rate{}, localErr.Invalid("rate can't start in the past")
+	}
+
+	var err error
+	switch {
+	case rate.TaskID != nil:
+		_, err = s.GetTask(ctx, *rate.TaskID)
+	case rate.ProjectID != nil:
+		_, err = s.GetProject(ctx, *rate.ProjectID)
+	default:
+		err = s.db.Where("id = ?", *rate.UserID).First(&model.User{}).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = localErr.NotFound("no user with that id")
+		}
+	}
+
+	if err != nil {
+		return model.Rate{}, err
+	}
+
+	err = s.db.Create(&rate).Error
+	if err != nil {
+		return model.Rate{}, err
+	}
+
+	return rate, nil
+}
+
+// GetEarnings multiplies the billable time of the closed sessions of the request by the rate that
+// applied when each session started, and sums the amounts per currency of the clients. The task
+// rate wins over the project rate, the project rate over the default rate of the client, and that
+// over the rate of the task owner.
+func (s *Storage) GetEarnings(ctx context.Context, request model.EarningsRequest) (model.EarningsReport, error) {
+	query := billableSessions(s.db, request.From, request.To)
+
+	if request.ID != uuid.Nil {
+		query = query.Where("tasks.user_id = ?", request.ID)
+	}
+
+	if request.ProjectID != uuid.Nil {
+		query = query.Where("tasks.project_id = ?", request.ProjectID)
+	}
+
+	var sessions []billableSession
+	err := query.Scan(&sessions).Error
+	if err != nil {
+		return model.EarningsReport{}, err
+	}
+
+	rates, err := loadRates(s.db, sessions)
+	if err != nil {
+		return model.EarningsReport{}, err
+	}
+
+	earnings := make(map[uuid.UUID]*model.TaskEarnings)
+	for _, session := range sessions {
+		task, ok := earnings[session.TaskID]
+		if !ok {
+			task = &model.TaskEarnings{TaskID: session.TaskID, TaskName: session.TaskName, ProjectID: session.ProjectID, Currency: session.Currency}
+			earnings[session.TaskID] = task
+		}
+
+		task.Time += session.Time
+
+		rate, ok := rates.at(session)
+		if !ok {
+			task.Unrated += session.Time
+			continue
+		}
+
+		task.Amount = task.Amount.Add(sessionAmount(rate, session.Time))
+	}
+
+	report := model.EarningsReport{Tasks: make([]model.TaskEarnings, 0, len(earnings)), Currencies: []model.CurrencyEarnings{}}
+	currencies := make(map[string]*model.CurrencyEarnings)
+	for _, task := range earnings {
+		report.Tasks = append(report.Tasks, *task)
+		report.Time += task.Time
+		report.Unrated += task.Unrated
+
+		currency, ok := currencies[task.Currency]
+		if !ok {
+			currency = &model.CurrencyEarnings{Currency: task.Currency}
+			currencies[task.Currency] = currency
+		}
+
+		currency.Time += task.Time
+		currency.Unrated += task.Unrated
+		currency.Amount = currency.Amount.Add(task.Amount)
+	}
+
+	for _, currency := range currencies {
+		report.Currencies = append(report.Currencies, *currency)
+	}
+
+	sort.Slice(report.Currencies, func(i, j int) bool {
+		return report.Currencies[i].Currency < report.Currencies[j].Currency
+	})
+
+	sort.Slice(report.Tasks, func(i, j int) bool {
+		if report.Tasks[i].Currency != report.Tasks[j].Currency {
+			return report.Tasks[i].Currency < report.Tasks[j].Currency
+		}
+
+		return report.Tasks[i].Amount.GreaterThan(report.Tasks[j].Amount)
+	})
+
+	return report, nil
+}
+
+// billableSession is a closed billable session with the task fields the rates depend on and the
+// currency of its client.
+type billableSession struct {
+	ID        uuid.UUID
+	TaskID    uuid.UUID
+	TaskName  string
+	UserID    uuid.UUID
+	ProjectID *uuid.UUID
+	ClientID  *uuid.UUID
+	Currency  string
+	StartedAt time.Time
+	Time      time.Duration
+	Note      string
+}
+
+// billableSessions selects the closed billable sessions started within [from, to]. A zero from or
+// to leaves the period open on that side.
+func billableSessions(db *gorm.DB, from time.Time, to time.Time) *gorm.DB {
+	query := db.Table("task_tracks").
+		Select("task_tracks.id, task_tracks.task_id, tasks.name AS task_name, tasks.user_id, tasks.project_id, projects.client_id, COALESCE(clients.currency, '') AS currency, task_tracks.started_at, task_tracks.time, task_tracks.note").
+		Joins("JOIN tasks ON tasks.id = task_tracks.task_id").
+		Joins("LEFT JOIN projects ON projects.id = tasks.project_id").
+		Joins("LEFT JOIN clients ON clients.id = projects.client_id").
+		Where("task_tracks.billable AND task_tracks.time IS NOT NULL AND task_tracks.deleted_at IS NULL AND tasks.deleted_at IS NULL").
+		Order("task_tracks.started_at")
+
+	if !from.IsZero() {
+		query = query.Where("task_tracks.started_at >= ?", from)
+	}
+
+	if !to.IsZero() {
+		query = query.Where("task_tracks.started_at <= ?", to)
+	}
+
+	return query
+}
+
+// rateBook holds the rates of users, clients, projects and tasks by their target, the earliest first.
+type rateBook map[uuid.UUID][]model.Rate
+
+// loadRates loads every rate that can apply to the sessions.
+func loadRates(db *gorm.DB, sessions []billableSession) (rateBook, error) {
+	book := make(rateBook)
+	if len(sessions) == 0 {
+		return book, nil
+	}
+
+	var targets []uuid.UUID
+	for _, session := range sessions {
+		targets = append(targets, session.rateTargets()...)
+	}
+
+	var rates []model.Rate
+	err := db.Where("task_id IN ? OR project_id IN ? OR client_id IN ? OR user_id IN ?", targets, targets, targets, targets).
+		Order("starts_at").Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rate := range rates {
+		book[rate.Target()] = append(book[rate.Target()], rate)
+	}
+
+	return book, nil
+}
+
+// rateTargets are the targets of the rates that can apply to the session, the most specific first.
+func (b *billableSession) rateTargets() []uuid.UUID {
+	targets := []uuid.UUID{b.TaskID}
+	if b.ProjectID != nil {
+		targets = append(targets, *b.ProjectID)
+	}
+
+	if b.ClientID != nil {
+		targets = append(targets, *b.ClientID)
+	}
+
+	return append(targets, b.UserID)
+}
+
+// at returns the most specific rate that applied when the session started.
+func (b rateBook) at(session billableSession) (decimal.Decimal, bool) {
+	for _, target := range session.rateTargets() {
+		rates := b[target]
+		for i := len(rates) - 1; i >= 0; i-- {
+			if !rates[i].StartsAt.After(session.StartedAt) {
+				return rates[i].Amount, true
+			}
+		}
+	}
+
+	return decimal.Decimal{}, false
+}
+
+// sessionAmount is the amount of the hourly rate for the duration, rounded to cents.
+func sessionAmount(rate decimal.Decimal, duration time.Duration) decimal.Decimal {
+	return rate.Mul(decimal.NewFromInt(int64(duration))).Div(decimal.NewFromInt(int64(time.Hour))).Round(2)
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func TestRateBookAt(t *testing.T) {
+	taskID, projectID, clientID, userID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	march := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	// book holds the user rate of 10 from March, raised to 20 in April, the client rate of 30 from
+	// March 10, the project rate of 40 from March 20 and the task rate of 50 from May.
+	book := rateBook{
+		userID: {
+			{UserID: &userID, Amount: decimal.NewFromInt(10), StartsAt: march},
+			{UserID: &userID, Amount: decimal.NewFromInt(20), StartsAt: march.AddDate(0, 1, 0)},
+		},
+		clientID:  {{ClientID: &clientID, Amount: decimal.NewFromInt(30), StartsAt: march.AddDate(0, 0, 9)}},
+		projectID: {{ProjectID: &projectID, Amount: decimal.NewFromInt(40), StartsAt: march.AddDate(0, 0, 19)}},
+		taskID:    {{TaskID: &taskID, Amount: decimal.NewFromInt(50), StartsAt: march.AddDate(0, 2, 0)}},
+	}
+
+	tests := []struct {
+		name      string
+		startedAt time.Time
+		project   bool
+		client    bool
+		rate      int64
+		rated     bool
+	}{
+		{"before every rate", march.Add(-time.Nanosecond), true, true, 0, false},
+		{"when the user rate starts", march, true, true, 10, true},
+		{"before the client rate starts", march.AddDate(0, 0, 9).Add(-time.Nanosecond), true, true, 10, true},
+		{"when the client rate starts", march.AddDate(0, 0, 9), true, true, 30, true},
+		{"when the project rate starts", march.AddDate(0, 0, 19), true, true, 40, true},
+		{"project rate over the newer user rate", march.AddDate(0, 1, 5), true, true, 40, true},
+		{"when the task rate starts", march.AddDate(0, 2, 0), true, true, 50, true},
+		{"without a project", march.AddDate(0, 0, 25), false, false, 10, true},
+		{"raised user rate without a project", march.AddDate(0, 1, 0), false, false, 20, true},
+		{"project without a client", march.AddDate(0, 0, 15), true, false, 10, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := billableSession{TaskID: taskID, UserID: userID, StartedAt: test.startedAt}
+			if test.project {
+				session.ProjectID = &projectID
+			}
+
+			if test.client {
+				session.ClientID = &clientID
+			}
+
+			rate, ok := book.at(session)
+			if ok != test.rated {
+				t.Fatalf("rated = %t, want %t", ok, test.rated)
+			}
+
+			if ok && !rate.Equal(decimal.NewFromInt(test.rate)) {
+				t.Errorf("rate = %s, want %d", rate, test.rate)
+			}
+		})
+	}
+}
+
+func TestSessionAmount(t *testing.T) {
+	tests := []struct {
+		rate     string
+		duration time.Duration
+		amount   string
+	}{
+		{"60", time.Hour, "60"},
+		{"60", 90 * time.Minute, "90"},
+		{"42.50", 26*time.Hour + 5*time.Minute, "1108.54"},
+		{"100", time.Second, "0.03"},
+		{"100", 0, "0"},
+	}
+
+	for _, test := range tests {
+		amount := sessionAmount(decimal.RequireFromString(test.rate), test.duration)
+		if !amount.Equal(decimal.RequireFromString(test.amount)) {
+			t.Errorf("%s for %s = %s, want %s", test.rate, test.duration, amount, test.amount)
+		}
+	}
+}
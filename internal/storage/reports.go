@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+// GetTimeBuckets sums the time of the closed sessions of the request per calendar bucket and task.
+// Only the time within the period counts, and the active time of a session crossing midnight, or
+// a week or month boundary, is split between the buckets it falls in. Rows come ordered by bucket,
+// then by task name.
+func (s *Storage) GetTimeBuckets(ctx context.Context, request model.BucketReportRequest) ([]model.BucketTotal, error) {
+	location, err := time.LoadLocation(request.Timezone)
+	if err != nil {
+		return nil, localErr.Invalid("unknown timezone " + request.Timezone)
+	}
+
+	query := s.db.Joins("JOIN tasks ON tasks.id = task_tracks.task_id AND tasks.deleted_at IS NULL").
+		Where("task_tracks.ended_at IS NOT NULL AND task_tracks.ended_at > ? AND task_tracks.started_at < ?", request.From, request.To)
+
+	if request.ID != uuid.Nil {
+		query = query.Where("tasks.user_id = ?", request.ID)
+	}
+
+	if request.ProjectID != uuid.Nil {
+		query = query.Where("tasks.project_id = ?", request.ProjectID)
+	}
+
+	var tracks []model.TaskTrack
+	err = query.Order("task_tracks.started_at").Preload("Pauses").Preload("Task").Find(&tracks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type bucketKey struct {
+		start  time.Time
+		taskID uuid.UUID
+	}
+
+	totals := make(map[bucketKey]*model.BucketTotal)
+	for _, track := range tracks {
+		splitIntoBuckets(track, request.Bucket, request.From, request.To, location, func(bucketStart time.Time, bucketEnd time.Time, d time.Duration) {
+			key := bucketKey{start: bucketStart, taskID: track.TaskID}
+			total, ok := totals[key]
+			if !ok {
+				total = &model.BucketTotal{
+					Bucket:   model.BucketLabel(request.Bucket, bucketStart),
+					Start:    bucketStart,
+					End:      bucketEnd,
+					TaskID:   track.TaskID,
+					TaskName: track.Task.Name,
+				}
+				totals[key] = total
+			}
+
+			total.Time += d
+		})
+	}
+
+	result := make([]model.BucketTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Start.Equal(result[j].Start) {
+			return result[i].Start.Before(result[j].Start)
+		}
+
+		if result[i].TaskName != result[j].TaskName {
+			return result[i].TaskName < result[j].TaskName
+		}
+
+		return result[i].TaskID.String() < result[j].TaskID.String()
+	})
+
+	return result, nil
+}
+
+// splitIntoBuckets passes the active time of the closed session within [from, to] to add per
+// calendar bucket, with the bucket bounds in location.
+func splitIntoBuckets(track model.TaskTrack, bucket string, from time.Time, to time.Time, location *time.Location, add func(bucketStart time.Time, bucketEnd time.Time, d time.Duration)) {
+	for _, interval := range track.ActiveIntervals() {
+		interval, ok := interval.Clip(from, to)
+		if !ok {
+			continue
+		}
+
+		for start := interval.Start; start.Before(interval.End); {
+			bucketStart := model.BucketStart(bucket, start.In(location))
+			bucketEnd := model.NextBucket(bucket, bucketStart)
+
+			end := interval.End
+			if bucketEnd.Before(end) {
+				end = bucketEnd
+			}
+
+			add(bucketStart, bucketEnd, end.Sub(start))
+			start = end
+		}
+	}
+}
+
+// GetTeamReport sums the time of the closed sessions of the users of the request that match the
+// filters, cut to the period as in CalcTime. It returns a page of the per-user totals, with the
+// users without sessions too, and the per-task and grand totals of all of the users.
+func (s *Storage) GetTeamReport(ctx context.Context, request model.TeamReportRequest, filters model.UserFilter, pagination utils.Pagination) (model.TeamTotals, utils.Pagination, error) {
+	users := s.db.Model(&model.User{}).Where(&model.User{Name: filters.NameFilter, Surname: filters.SurnameFilter, Address: filters.AddressFilter, Patronymic: filters.PatronymicFilter})
+	if len(request.UserIDs) > 0 {
+		users = users.Where("users.id IN ?", request.UserIDs)
+	}
+
+	columns := periodColumns(request.From, request.To)
+	tracks := closedTracks(s.db, request.From, request.To).
+		Where("tasks.user_id IN (?)", users.Session(&gorm.Session{}).Select("users.id"))
+
+	var userRows []model.UserTotal
+	perUser := tracks.Session(&gorm.Session{}).
+		Select(fmt.Sprintf("tasks.user_id, SUM(%s)::bigint AS time, COUNT(*) AS sessions_count", columns.time), columns.vars()...).
+		Group("tasks.user_id")
+
+	err := users.Session(&gorm.Session{}).
+		Scopes(utils.Paginate([]model.User{}, &pagination, users.Session(&gorm.Session{}))).
+		Select("users.id AS user_id, users.name, users.surname, COALESCE(totals.time, 0) AS time, COALESCE(totals.sessions_count, 0) AS sessions_count").
+		Joins("LEFT JOIN (?) AS totals ON totals.user_id = users.id", perUser).
+		Scan(&userRows).Error
+	if err != nil {
+		return model.TeamTotals{}, pagination, err
+	}
+
+	var taskRows []model.TimeTotal
+	err = tracks.Session(&gorm.Session{}).
+		Select(fmt.Sprintf("task_tracks.task_id AS id, tasks.name, SUM(%s)::bigint AS time, COUNT(*) AS sessions_count", columns.time), columns.vars()...).
+		Group("task_tracks.task_id, tasks.name").
+		Order("time DESC, tasks.name").
+		Scan(&taskRows).Error
+	if err != nil {
+		return model.TeamTotals{}, pagination, err
+	}
+
+	totals := model.TeamTotals{Users: userRows, Tasks: taskRows}
+	if totals.Users == nil {
+		totals.Users = []model.UserTotal{}
+	}
+
+	if totals.Tasks == nil {
+		totals.Tasks = []model.TimeTotal{}
+	}
+
+	for _, task := range totals.Tasks {
+		totals.Time += task.Time
+		totals.SessionsCount += task.SessionsCount
+	}
+
+	return totals, pagination, nil
+}
+
+// ExportTracks passes the closed sessions of the filters to write one by one, as they are read from
+// the database, in the order of their start. Sessions crossing the boundaries of the period are cut
+// to their part inside it.
+func (s *Storage) ExportTracks(ctx context.Context, filters model.TrackFilter, write func(model.SessionRow) error) error {
+	query := trackFilters(closedTracks(s.db, filters.FromFilter, filters.ToFilter), filters)
+
+	return s.exportSessions(query, periodColumns(filters.FromFilter, filters.ToFilter), write)
+}
+
+// ExportCalcTime passes the closed sessions summed by the calc-time request to write one by one, as
+// ExportTracks does.
+func (s *Storage) ExportCalcTime(ctx context.Context, request model.CalcTimeRequest, write func(model.SessionRow) error) error {
+	return s.exportSessions(calcTimeTracks(s.db, request), periodColumns(request.From, request.To), write)
+}
+
+// ExportCalendar passes the closed sessions of the user that intersect the period of the filter to
+// write one by one, as ExportTracks does, but with their whole bounds and time.
+func (s *Storage) ExportCalendar(ctx context.Context, userID uuid.UUID, filter model.CalendarFilter, write func(model.SessionRow) error) error {
+	query := closedTracks(s.db, filter.From, filter.To).Where("tasks.user_id = ?", userID)
+
+	return s.exportSessions(query, periodColumns(time.Time{}, time.Time{}), write)
+}
+
+// exportSessions reads the sessions of the query on task_tracks joined with tasks row by row, so
+// that an export never holds more than one of them.
+func (s *Storage) exportSessions(query *gorm.DB, columns trackColumns, write func(model.SessionRow) error) error {
+	rows, err := query.Joins("JOIN users ON users.id = tasks.user_id").
+		Select(fmt.Sprintf("task_tracks.id, users.name AS user_name, users.surname AS user_surname, tasks.name AS task_name, "+
+			"%s AS started_at, %s AS ended_at, (%s)::bigint AS time, task_tracks.note",
+			columns.startedAt, columns.endedAt, columns.time), columns.vars()...).
+		Order("task_tracks.started_at, task_tracks.id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.SessionRow
+
+		err = s.db.ScanRows(rows, &row)
+		if err != nil {
+			return err
+		}
+
+		err = write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+func TestGetTeamReportArgs(t *testing.T) {
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		request model.TeamReportRequest
+	}{
+		{"no period", model.TeamReportRequest{}},
+		{"users without period", model.TeamReportRequest{UserIDs: []uuid.UUID{uuid.New(), uuid.New()}}},
+		{"period", model.TeamReportRequest{From: from, To: from.AddDate(0, 0, 7)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, statements := dryRunStorage(t)
+
+			_, _, err := s.GetTeamReport(context.Background(), test.request, model.UserFilter{}, utils.Pagination{})
+			if dryRunError(err) != nil {
+				t.Fatal(err)
+			}
+
+			checkPlaceholders(t, *statements)
+		})
+	}
+}
+
+func TestExportArgs(t *testing.T) {
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	userID := uuid.New()
+
+	tests := []struct {
+		name   string
+		export func(s *Storage, write func(model.SessionRow) error) error
+	}{
+		{"tracks without period", func(s *Storage, write func(model.SessionRow) error) error {
+			return s.ExportTracks(context.Background(), model.TrackFilter{UserIDFilter: userID}, write)
+		}},
+		{"tracks with period", func(s *Storage, write func(model.SessionRow) error) error {
+			return s.ExportTracks(context.Background(), model.TrackFilter{UserIDFilter: userID, FromFilter: from, ToFilter: to}, write)
+		}},
+		{"calc-time without period", func(s *Storage, write func(model.SessionRow) error) error {
+			return s.ExportCalcTime(context.Background(), model.CalcTimeRequest{ID: userID}, write)
+		}},
+		{"calc-time with period", func(s *Storage, write func(model.SessionRow) error) error {
+			return s.ExportCalcTime(context.Background(), model.CalcTimeRequest{ID: userID, From: from, To: to}, write)
+		}},
+		{"calendar without period", func(s *Storage, write func(model.SessionRow) error) error {
+			return s.ExportCalendar(context.Background(), userID, model.CalendarFilter{}, write)
+		}},
+		{"calendar with period", func(s *Storage, write func(model.SessionRow) error) error {
+			return s.ExportCalendar(context.Background(), userID, model.CalendarFilter{From: from, To: to}, write)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, statements := dryRunStorage(t)
+
+			err := test.export(s, func(model.SessionRow) error { return nil })
+			if dryRunError(err) != nil {
+				t.Fatal(err)
+			}
+
+			checkPlaceholders(t, *statements)
+		})
+	}
+}
+
+func TestSplitIntoBuckets(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// closedTrack returns a closed session with an optional pause given as two instants.
+	closedTrack := func(start time.Time, end time.Time, pause ...time.Time) model.TaskTrack {
+		track := model.TaskTrack{StartedAt: start}
+		if len(pause) == 2 {
+			track.Pauses = []model.TaskTrackPause{{PausedAt: pause[0], ResumedAt: &pause[1]}}
+		}
+
+		track.Close(end)
+
+		return track
+	}
+
+	tests := []struct {
+		name     string
+		track    model.TaskTrack
+		bucket   string
+		from     time.Time
+		to       time.Time
+		location *time.Location
+		want     map[string]time.Duration
+	}{
+		{"within a day", closedTrack(time.Date(2024, time.March, 5, 9, 0, 0, 0, time.UTC), time.Date(2024, time.March, 5, 17, 0, 0, 0, time.UTC)),
+			model.BucketDay, time.Time{}, time.Time{}, time.UTC,
+			map[string]time.Duration{"2024-03-05": 8 * time.Hour}},
+		{"crossing midnight with a pause", closedTrack(time.Date(2024, time.March, 5, 22, 0, 0, 0, time.UTC), time.Date(2024, time.March, 6, 3, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 5, 23, 30, 0, 0, time.UTC), time.Date(2024, time.March, 6, 0, 30, 0, 0, time.UTC)),
+			model.BucketDay, time.Time{}, time.Time{}, time.UTC,
+			map[string]time.Duration{"2024-03-05": 90 * time.Minute, "2024-03-06": 150 * time.Minute}},
+		{"midnight of the location", closedTrack(time.Date(2024, time.March, 5, 22, 0, 0, 0, time.UTC), time.Date(2024, time.March, 6, 1, 0, 0, 0, time.UTC)),
+			model.BucketDay, time.Time{}, time.Time{}, berlin,
+			map[string]time.Duration{"2024-03-05": time.Hour, "2024-03-06": 2 * time.Hour}},
+		{"over the spring forward night", closedTrack(time.Date(2024, time.March, 30, 22, 0, 0, 0, berlin), time.Date(2024, time.March, 31, 4, 0, 0, 0, berlin)),
+			model.BucketDay, time.Time{}, time.Time{}, berlin,
+			map[string]time.Duration{"2024-03-30": 2 * time.Hour, "2024-03-31": 3 * time.Hour}},
+		{"cut to the period", closedTrack(time.Date(2024, time.March, 5, 22, 0, 0, 0, time.UTC), time.Date(2024, time.March, 6, 3, 0, 0, 0, time.UTC)),
+			model.BucketDay, time.Date(2024, time.March, 5, 23, 0, 0, 0, time.UTC), time.Date(2024, time.March, 6, 2, 0, 0, 0, time.UTC), time.UTC,
+			map[string]time.Duration{"2024-03-05": time.Hour, "2024-03-06": 2 * time.Hour}},
+		{"into week 53", closedTrack(time.Date(2020, time.December, 27, 20, 0, 0, 0, time.UTC), time.Date(2020, time.December, 28, 4, 0, 0, 0, time.UTC)),
+			model.BucketWeek, time.Time{}, time.Time{}, time.UTC,
+			map[string]time.Duration{"2020-W52": 4 * time.Hour, "2020-W53": 4 * time.Hour}},
+		{"over New Year", closedTrack(time.Date(2024, time.December, 31, 23, 0, 0, 0, time.UTC), time.Date(2025, time.January, 1, 1, 0, 0, 0, time.UTC)),
+			model.BucketMonth, time.Time{}, time.Time{}, time.UTC,
+			map[string]time.Duration{"2024-12": time.Hour, "2025-01": time.Hour}},
+		{"outside of the period", closedTrack(time.Date(2024, time.March, 5, 9, 0, 0, 0, time.UTC), time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)),
+			model.BucketDay, time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC), time.Time{}, time.UTC,
+			map[string]time.Duration{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := make(map[string]time.Duration)
+			splitIntoBuckets(test.track, test.bucket, test.from, test.to, test.location, func(bucketStart time.Time, bucketEnd time.Time, d time.Duration) {
+				if !bucketEnd.Equal(model.NextBucket(test.bucket, bucketStart)) {
+					t.Errorf("bucket %s ends at %s", bucketStart, bucketEnd)
+				}
+
+				got[model.BucketLabel(test.bucket, bucketStart)] += d
+			})
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("buckets %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/google/uuid"
 	_ "github.com/lib/pq" // Initializes the postgres driver
 	"gorm.io/gorm"
+	"time"
+	"timeTracker/config"
 	"timeTracker/internal/model"
 	"timeTracker/internal/utils"
 )
@@ -15,9 +17,61 @@ type StorageInterface interface {
 	AddUser(ctx context.Context, user model.User) (model.User, error)
 	DeleteUser(ctx context.Context, userID uuid.UUID) (bool, error)
 	UpdateUser(ctx context.Context, user model.User) (model.User, error)
+	ResetCalendarToken(ctx context.Context, userID uuid.UUID) (string, error)
+	GetCalendarUser(ctx context.Context, token string) (model.User, error)
+	GetTasks(ctx context.Context, filters model.TaskFilter, pagination utils.Pagination) ([]model.Task, error)
+	GetTask(ctx context.Context, taskID uuid.UUID) (model.Task, error)
+	AddTask(ctx context.Context, task model.Task) (model.Task, error)
+	UpdateTask(ctx context.Context, taskID uuid.UUID, update model.TaskUpdate) (model.Task, error)
+	UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, status string) (model.Task, error)
+	DeleteTask(ctx context.Context, taskID uuid.UUID) (bool, error)
+	GetEstimates(ctx context.Context, filters model.EstimateFilter) (model.EstimateReport, error)
 	TrackTime(ctx context.Context, trackModel model.TaskTrack) (model.TaskTrack, error)
-	StopTrackTime(ctx context.Context, taskId uuid.UUID) (model.TaskTrack, error)
-	CalcTime(ctx context.Context, userID uuid.UUID) ([]model.TaskTrack, error)
+	StopTrackTime(ctx context.Context, taskId uuid.UUID, note *string) (model.TaskTrack, error)
+	AddTrack(ctx context.Context, trackModel model.TaskTrack) (model.TaskTrack, error)
+	ImportTracks(ctx context.Context, request model.ImportTracksRequest, events []model.ImportEvent) (model.ImportResult, error)
+	UpdateTrack(ctx context.Context, trackModel model.TaskTrack) (model.TaskTrack, error)
+	SplitTrack(ctx context.Context, trackID uuid.UUID, at time.Time, taskID uuid.UUID) ([]model.TaskTrack, error)
+	MergeTracks(ctx context.Context, trackIDs []uuid.UUID) (model.TaskTrack, error)
+	GetTrackRevisions(ctx context.Context, trackID uuid.UUID) ([]model.TaskTrackRevision, error)
+	GetOverlaps(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time) ([]model.TrackOverlap, error)
+	PauseTrackTime(ctx context.Context, taskId uuid.UUID) (model.TaskTrack, error)
+	ResumeTrackTime(ctx context.Context, taskId uuid.UUID) (model.TaskTrack, error)
+	GetTracks(ctx context.Context, filters model.TrackFilter, pagination utils.Pagination) ([]model.TaskTrack, error)
+	CalcTime(ctx context.Context, request model.CalcTimeRequest) ([]model.TimeTotal, error)
+	GetTimeBuckets(ctx context.Context, request model.BucketReportRequest) ([]model.BucketTotal, error)
+	ExportTracks(ctx context.Context, filters model.TrackFilter, write func(model.SessionRow) error) error
+	ExportCalcTime(ctx context.Context, request model.CalcTimeRequest, write func(model.SessionRow) error) error
+	ExportCalendar(ctx context.Context, userID uuid.UUID, filter model.CalendarFilter, write func(model.SessionRow) error) error
+	GetTeamReport(ctx context.Context, request model.TeamReportRequest, filters model.UserFilter, pagination utils.Pagination) (model.TeamTotals, utils.Pagination, error)
+	GetTimesheet(ctx context.Context, request model.TimesheetRequest) (model.Timesheet, error)
+	UpdateTimesheet(ctx context.Context, request model.TimesheetRequest, cells []model.TimesheetCell) (model.Timesheet, error)
+	GetProjects(ctx context.Context, filters model.ProjectFilter, pagination utils.Pagination) ([]model.Project, error)
+	GetProject(ctx context.Context, projectID uuid.UUID) (model.Project, error)
+	AddProject(ctx context.Context, project model.Project) (model.Project, error)
+	UpdateProject(ctx context.Context, project model.Project) (model.Project, error)
+	DeleteProject(ctx context.Context, projectID uuid.UUID) (bool, error)
+	GetClients(ctx context.Context, filters model.ClientFilter, pagination utils.Pagination) ([]model.Client, error)
+	GetClient(ctx context.Context, clientID uuid.UUID) (model.Client, error)
+	AddClient(ctx context.Context, client model.Client) (model.Client, error)
+	UpdateClient(ctx context.Context, client model.Client) (model.Client, error)
+	DeleteClient(ctx context.Context, clientID uuid.UUID) (bool, error)
+	GetClientReport(ctx context.Context, request model.ClientReportRequest) ([]model.ClientTotal, error)
+	GetInvoices(ctx context.Context, filters model.InvoiceFilter, pagination utils.Pagination) ([]model.Invoice, error)
+	GetInvoice(ctx context.Context, invoiceID uuid.UUID) (model.Invoice, error)
+	AddInvoice(ctx context.Context, request model.AddInvoiceRequest) (model.Invoice, error)
+	UpdateInvoiceStatus(ctx context.Context, invoiceID uuid.UUID, status string) (model.Invoice, error)
+	GetTags(ctx context.Context, pagination utils.Pagination) ([]model.Tag, error)
+	AddTag(ctx context.Context, tag model.Tag) (model.Tag, error)
+	UpdateTag(ctx context.Context, tag model.Tag) (model.Tag, error)
+	DeleteTag(ctx context.Context, tagID uuid.UUID) (bool, error)
+	SetTaskTags(ctx context.Context, taskID uuid.UUID, tagIDs []uuid.UUID) (model.Task, error)
+	SetTrackTags(ctx context.Context, trackID uuid.UUID, tagIDs []uuid.UUID) (model.TaskTrack, error)
+	SetTrackNote(ctx context.Context, trackID uuid.UUID, note string) (model.TaskTrack, error)
+	SetTrackBillable(ctx context.Context, trackID uuid.UUID, billable bool) (model.TaskTrack, error)
+	GetRates(ctx context.Context, filters model.RateFilter) ([]model.Rate, error)
+	AddRate(ctx context.Context, rate model.Rate) (model.Rate, error)
+	GetEarnings(ctx context.Context, request model.EarningsRequest) (model.EarningsReport, error)
 	//GetBook(ctx context.Context, id int) (model.Book, error)
 	//GetBooks(ctx context.Context) ([]model.Book, error)
 	//UpdateBook(ctx context.Context, book model.UpdateBookRequest) (int, error)
@@ -25,9 +79,11 @@ type StorageInterface interface {
 	//VerifyBookExists(ctx context.Context, id int) (bool, error)
 }
 
-// Storage contains an SQL db. Storage implements the StorageInterface.
+// Storage contains an SQL db and the app config with the policies it enforces.
+// Storage implements the StorageInterface.
 type Storage struct {
-	db *gorm.DB
+	db     *gorm.DB
+	config config.ApiEnvConfig
 }
 
 func (s *Storage) Close() error {
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"regexp"
+	"testing"
+)
+
+// builtStatement is the SQL of a query built by a dry run storage with its args.
+type builtStatement struct {
+	sql  string
+	vars []interface{}
+}
+
+// dryRunStorage returns a storage that builds the SQL of its queries without a database, and the
+// statements it has built so far.
+func dryRunStorage(t *testing.T) (*Storage, *[]builtStatement) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statements []builtStatement
+	capture := func(db *gorm.DB) {
+		// GORM builds the subqueries passed as args with the Discard logger.
+		if db.Logger == logger.Discard {
+			return
+		}
+
+		statements = append(statements, builtStatement{
+			sql:  db.Statement.SQL.String(),
+			vars: append([]interface{}(nil), db.Statement.Vars...),
+		})
+	}
+
+	err = db.Callback().Query().After("gorm:query").Register("test:capture", capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Callback().Row().After("gorm:row").Register("test:capture", capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Storage{db: db}, &statements
+}
+
+// dryRunError returns err unless it only tells that rows can't be read in a dry run.
+func dryRunError(err error) error {
+	if errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+		return nil
+	}
+
+	return err
+}
+
+var placeholder = regexp.MustCompile(`\$(\d+)`)
+
+// checkPlaceholders fails the test when the args of a statement don't match its placeholders one
+// to one, which Postgres rejects.
+func checkPlaceholders(t *testing.T, statements []builtStatement) {
+	t.Helper()
+
+	if len(statements) == 0 {
+		t.Fatal("no statements built")
+	}
+
+	for _, statement := range statements {
+		used := make(map[string]bool)
+		for _, match := range placeholder.FindAllStringSubmatch(statement.sql, -1) {
+			used[match[1]] = true
+		}
+
+		for i, v := range statement.vars {
+			if !used[fmt.Sprint(i+1)] {
+				t.Errorf("arg %d (%v) has no placeholder in %s", i+1, v, statement.sql)
+			}
+		}
+
+		if len(used) != len(statement.vars) {
+			t.Errorf("%d placeholders for %d args in %s", len(used), len(statement.vars), statement.sql)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+func (s *Storage) GetTags(ctx context.Context, pagination utils.Pagination) ([]model.Tag, error) {
+	var tags []model.Tag
+
+	err := s.db.Scopes(utils.Paginate(tags, &pagination, s.db)).Find(&tags).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *Storage) AddTag(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	err := s.checkTagName(tag)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	err = s.db.Create(&tag).Error
+
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+func (s *Storage) UpdateTag(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	var savedTag model.Tag
+	err := s.db.Where("id = ?", tag.ID).First(&savedTag).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Tag{}, localErr.NotFound("no tag with that id")
+	}
+
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	err = s.checkTagName(tag)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	savedTag.Name = tag.Name
+
+	err = s.db.Save(&savedTag).Error
+
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	return savedTag, nil
+}
+
+// DeleteTag deletes the tag and removes it from all tasks and sessions.
+func (s *Storage) DeleteTag(ctx context.Context, tagID uuid.UUID) (bool, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", tagID).Delete(&model.Tag{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return localErr.NotFound("no tag with that id")
+		}
+
+		err := tx.Exec("DELETE FROM task_tags WHERE tag_id = ?", tagID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec("DELETE FROM task_track_tags WHERE tag_id = ?", tagID).Error
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SetTaskTags replaces the tags of the task.
+func (s *Storage) SetTaskTags(ctx context.Context, taskID uuid.UUID, tagIDs []uuid.UUID) (model.Task, error) {
+	task, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	tags, err := s.findTags(tagIDs)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	err = s.db.Model(&task).Association("Tags").Replace(tags)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return task, nil
+}
+
+// SetTrackTags replaces the tags of the session, unless it is billed.
+func (s *Storage) SetTrackTags(ctx context.Context, trackID uuid.UUID, tagIDs []uuid.UUID) (model.TaskTrack, error) {
+	var track model.TaskTrack
+	err := s.db.Where("id = ?", trackID).First(&track).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.TaskTrack{}, localErr.NotFound("no session with that id")
+	}
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	err = checkNotBilled(&track)
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	tags, err := s.findTags(tagIDs)
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	err = s.db.Model(&track).Association("Tags").Replace(tags)
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return track, nil
+}
+
+// findTags loads the tags with the ids and fails if any of them doesn't exist. Repeated ids are
+// loaded once.
+func (s *Storage) findTags(ids []uuid.UUID) ([]model.Tag, error) {
+	tags := []model.Tag{}
+	if len(ids) == 0 {
+		return tags, nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	tagIDs := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			tagIDs = append(tagIDs, id)
+		}
+	}
+
+	err := s.db.Where("id IN ?", tagIDs).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tags) != len(tagIDs) {
+		return nil, localErr.NotFound("no tag with that id")
+	}
+
+	return tags, nil
+}
+
+// checkTagName checks that no other tag has the name of the tag.
+func (s *Storage) checkTagName(tag model.Tag) error {
+	var sameName int64
+	err := s.db.Model(&model.Tag{}).Where("name = ? AND id <> ?", tag.Name, tag.ID).Count(&sameName).Error
+
+	if err != nil {
+		return err
+	}
+
+	if sameName > 0 {
+		return localErr.Conflict("tag with that name already exists", nil)
+	}
+
+	return nil
+}
+
+// tagsCondition limits the query on task_tracks joined with tasks to the sessions that have,
+// or with exclude don't have, any of the tags, either on the session or on its task.
+func tagsCondition(query *gorm.DB, tags []string, exclude bool) *gorm.DB {
+	if len(tags) == 0 {
+		return query
+	}
+
+	condition := `EXISTS (SELECT 1 FROM tags WHERE tags.deleted_at IS NULL AND tags.name IN ? AND (
+		tags.id IN (SELECT tag_id FROM task_tags WHERE task_tags.task_id = tasks.id) OR
+		tags.id IN (SELECT tag_id FROM task_track_tags WHERE task_track_tags.task_track_id = task_tracks.id)))`
+
+	if exclude {
+		condition = "NOT " + condition
+	}
+
+	return query.Where(condition, tags)
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
+)
+
+func (s *Storage) GetTasks(ctx context.Context, filters model.TaskFilter, pagination utils.Pagination) ([]model.Task, error) {
+	var tasks []model.Task
+
+	query := s.db.Model(&model.Task{}).Where(&model.Task{UserID: filters.UserIDFilter, Name: filters.NameFilter, Status: filters.StatusFilter})
+
+	if filters.ProjectIDFilter != uuid.Nil {
+		query = query.Where("project_id = ?", filters.ProjectIDFilter)
+	}
+
+	if filters.ParentIDFilter != uuid.Nil {
+		query = query.Where("parent_id = ?", filters.ParentIDFilter)
+	}
+
+	err := query.Scopes(utils.Paginate(tasks, &pagination, query.Session(&gorm.Session{}))).Preload("Tags").Find(&tasks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (s *Storage) GetTask(ctx context.Context, taskID uuid.UUID) (model.Task, error) {
+	var task model.Task
+	err := s.db.Where("id = ?", taskID).Preload("Tags").First(&task).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Task{}, localErr.NotFound("no task with that id")
+	}
+
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return task, nil
+}
+
+func (s *Storage) AddTask(ctx context.Context, task model.Task) (model.Task, error) {
+	var user model.User
+	err := s.db.Where("id = ?", task.UserID).First(&user).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Task{}, localErr.NotFound("no user with that id")
+	}
+
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	if task.ProjectID != nil {
+		if _, err := s.GetProject(ctx, *task.ProjectID); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	err = s.checkParent(ctx, task)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	err = s.db.Omit(clause.Associations).Create(&task).Error
+
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return task, nil
+}
+
+// UpdateTask applies the changes of update to the task. A task with billed sessions can't move to
+// another project.
+func (s *Storage) UpdateTask(ctx context.Context, taskID uuid.UUID, update model.TaskUpdate) (model.Task, error) {
+	savedTask, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	if update.Name != nil {
+		savedTask.Name = *update.Name
+	}
+
+	if update.ProjectID != nil {
+		projectID := optionalID(*update.ProjectID)
+
+		if !sameID(savedTask.ProjectID, projectID) {
+			if projectID != nil {
+				if _, err := s.GetProject(ctx, *projectID); err != nil {
+					return model.Task{}, err
+				}
+			}
+
+			err = checkTaskNotBilled(s.db, savedTask.ID)
+			if err != nil {
+				return model.Task{}, err
+			}
+		}
+
+		savedTask.ProjectID = projectID
+	}
+
+	if update.ParentID != nil {
+		savedTask.ParentID = optionalID(*update.ParentID)
+
+		err = s.checkParent(ctx, savedTask)
+		if err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	if update.Estimate != nil {
+		savedTask.Estimate = nil
+		if *update.Estimate != 0 {
+			savedTask.Estimate = update.Estimate
+		}
+	}
+
+	if update.Billable != nil {
+		savedTask.Billable = *update.Billable
+	}
+
+	err = s.db.Omit(clause.Associations).Save(&savedTask).Error
+
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return savedTask, nil
+}
+
+// optionalID returns nil for the nil uuid, which removes an optional reference.
+func optionalID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+
+	return &id
+}
+
+// UpdateTaskStatus moves the task to the status, if the lifecycle allows it. A task can't be
+// finished while it is being tracked.
+func (s *Storage) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, status string) (model.Task, error) {
+	task, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	if task.Status == status {
+		return task, nil
+	}
+
+	if !task.CanMoveTo(status) {
+		return model.Task{}, localErr.Invalid(fmt.Sprintf("task can't move from %s to %s", task.Status, status))
+	}
+
+	if status == model.TaskStatusDone || status == model.TaskStatusCancelled {
+		var openTracks int64
+		err = s.db.Model(&model.TaskTrack{}).Where("task_id = ? AND ended_at IS NULL", taskID).Count(&openTracks).Error
+
+		if err != nil {
+			return model.Task{}, err
+		}
+
+		if openTracks > 0 {
+			return model.Task{}, localErr.Conflict("task is being tracked", nil)
+		}
+	}
+
+	err = s.db.Model(&task).Update("status", status).Error
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return task, nil
+}
+
+// DeleteTask deletes the task. Its subtasks are moved to the parent of the task.
+func (s *Storage) DeleteTask(ctx context.Context, taskID uuid.UUID) (bool, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var task model.Task
+		err := tx.Where("id = ?", taskID).First(&task).Error
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return localErr.NotFound("no task with that id")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.Task{}).Where("parent_id = ?", taskID).Update("parent_id", task.ParentID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&task).Error
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// checkParent checks that the parent of the task exists, belongs to the same user and is not
+// the task itself or one of its subtasks.
+func (s *Storage) checkParent(ctx context.Context, task model.Task) error {
+	if task.ParentID == nil {
+		return nil
+	}
+
+	parent, err := s.GetTask(ctx, *task.ParentID)
+	if errors.Is(err, localErr.ErrNotFound) {
+		return localErr.NotFound("no parent task with that id")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if parent.UserID != task.UserID {
+		return localErr.Invalid("parent task belongs to another user")
+	}
+
+	ancestor := parent
+	for {
+		if ancestor.ID == task.ID {
+			return localErr.Invalid("task can't be a subtask of itself or of its subtasks")
+		}
+
+		if ancestor.ParentID == nil {
+			return nil
+		}
+
+		ancestor, err = s.GetTask(ctx, *ancestor.ParentID)
+		if err != nil {
+			return err
+		}
+	}
+}
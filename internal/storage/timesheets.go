@@ -0,0 +1,336 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+	localErr "timeTracker/internal/errors"
+	"timeTracker/internal/model"
+)
+
+// timesheetDayStart is when the entries added by a timesheet begin on a day without sessions.
+const timesheetDayStart = 9 * time.Hour
+
+// GetTimesheet sums the active time of the closed sessions of the request within the ISO week per
+// user, task and day. The time of a session crossing midnight is split between the days. Rows come
+// ordered by user surname and name, then by task name.
+func (s *Storage) GetTimesheet(ctx context.Context, request model.TimesheetRequest) (model.Timesheet, error) {
+	location, monday, err := timesheetWeek(request)
+	if err != nil {
+		return model.Timesheet{}, err
+	}
+
+	timesheet := model.Timesheet{Week: request.Week, Rows: []model.TimesheetRow{}}
+	for day := range timesheet.Days {
+		timesheet.Days[day] = monday.AddDate(0, 0, day)
+	}
+
+	sunday := monday.AddDate(0, 0, len(timesheet.Days))
+
+	query := s.db.Joins("JOIN tasks ON tasks.id = task_tracks.task_id AND tasks.deleted_at IS NULL").
+		Where("task_tracks.ended_at IS NOT NULL AND task_tracks.ended_at > ? AND task_tracks.started_at < ?", monday, sunday)
+
+	if request.UserID != uuid.Nil {
+		query = query.Where("tasks.user_id = ?", request.UserID)
+	}
+
+	if request.ProjectID != uuid.Nil {
+		query = query.Where("tasks.project_id = ?", request.ProjectID)
+	}
+
+	var tracks []model.TaskTrack
+	err = query.Preload("Pauses").Preload("Task.User").Find(&tracks).Error
+	if err != nil {
+		return model.Timesheet{}, err
+	}
+
+	rows := make(map[uuid.UUID]*model.TimesheetRow)
+	for _, track := range tracks {
+		row, ok := rows[track.TaskID]
+		if !ok {
+			row = &model.TimesheetRow{
+				UserID:      track.Task.UserID,
+				UserName:    track.Task.User.Name,
+				UserSurname: track.Task.User.Surname,
+				TaskID:      track.TaskID,
+				TaskName:    track.Task.Name,
+			}
+			rows[track.TaskID] = row
+		}
+
+		splitIntoBuckets(track, model.BucketDay, monday, sunday, location, func(dayStart time.Time, _ time.Time, d time.Duration) {
+			for day := range timesheet.Days {
+				if timesheet.Days[day].Equal(dayStart) {
+					row.Days[day] += d
+					row.Total += d
+					timesheet.DayTotals[day] += d
+					timesheet.Total += d
+				}
+			}
+		})
+	}
+
+	for _, row := range rows {
+		timesheet.Rows = append(timesheet.Rows, *row)
+	}
+
+	sort.Slice(timesheet.Rows, func(i, j int) bool {
+		a, b := timesheet.Rows[i], timesheet.Rows[j]
+		if a.UserSurname != b.UserSurname {
+			return a.UserSurname < b.UserSurname
+		}
+
+		if a.UserName != b.UserName {
+			return a.UserName < b.UserName
+		}
+
+		if a.UserID != b.UserID {
+			return a.UserID.String() < b.UserID.String()
+		}
+
+		if a.TaskName != b.TaskName {
+			return a.TaskName < b.TaskName
+		}
+
+		return a.TaskID.String() < b.TaskID.String()
+	})
+
+	return timesheet, nil
+}
+
+// UpdateTimesheet makes the time of each cell the time tracked by the user on its task and day, and
+// returns the timesheet of the user. Only manual sessions that are within the day, unpaused and not
+// billed are changed: time is added as a new manual session in the first gap from 9:00 on between
+// the sessions of the user, counting sessions crossing midnight and running timers, and removed
+// from the latest manual sessions, which are shortened or deleted with a revision. The time of the
+// other sessions can't be removed.
+func (s *Storage) UpdateTimesheet(ctx context.Context, request model.TimesheetRequest, cells []model.TimesheetCell) (model.Timesheet, error) {
+	_, monday, err := timesheetWeek(request)
+	if err != nil {
+		return model.Timesheet{}, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		tasks := make(map[uuid.UUID]model.Task)
+		for _, cell := range cells {
+			if _, ok := tasks[cell.TaskID]; ok {
+				continue
+			}
+
+			var task model.Task
+			err := tx.Where("id = ?", cell.TaskID).Limit(1).Find(&task).Error
+			if err != nil {
+				return err
+			}
+
+			if task.ID == uuid.Nil {
+				return localErr.NotFound("no task with that id")
+			}
+
+			if task.UserID != request.UserID {
+				return localErr.Invalid(fmt.Sprintf("task %s is not of the user", task.Name))
+			}
+
+			tasks[task.ID] = task
+		}
+
+		tracks, err := userClosedTracks(tx, request.UserID, monday, monday.AddDate(0, 0, 7))
+		if err != nil {
+			return err
+		}
+
+		var running []model.TaskTrack
+		err = tx.Joins("JOIN tasks ON tasks.id = task_tracks.task_id AND tasks.deleted_at IS NULL").
+			Where("tasks.user_id = ? AND task_tracks.ended_at IS NULL", request.UserID).
+			Find(&running).Error
+		if err != nil {
+			return err
+		}
+
+		for _, cell := range cells {
+			dayStart := monday.AddDate(0, 0, cell.Day)
+
+			tracks, err = s.setTimesheetCell(tx, tasks[cell.TaskID], tracks, running, dayStart, dayStart.AddDate(0, 0, 1), cell.Time)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return model.Timesheet{}, err
+	}
+
+	return s.GetTimesheet(ctx, model.TimesheetRequest{UserID: request.UserID, Week: request.Week, Timezone: request.Timezone})
+}
+
+// setTimesheetCell changes the manual sessions of the task on the day [dayStart, dayEnd) so that
+// the time tracked on it is target. tracks are the closed sessions of the user around the day and
+// running are the running timers of the user; the updated closed sessions are returned.
+func (s *Storage) setTimesheetCell(tx *gorm.DB, task model.Task, tracks []model.TaskTrack, running []model.TaskTrack, dayStart time.Time, dayEnd time.Time, target time.Duration) ([]model.TaskTrack, error) {
+	var fixed, adjustable time.Duration
+	var manual []int
+
+	for i, track := range tracks {
+		if track.TaskID != task.ID {
+			continue
+		}
+
+		if track.Source == model.TrackSourceManual && track.InvoiceID == nil && track.PausesCount == 0 &&
+			!track.StartedAt.Before(dayStart) && !track.EndedAt.After(dayEnd) {
+			manual = append(manual, i)
+			adjustable += *track.Time
+			continue
+		}
+
+		for _, interval := range track.ActiveIntervals() {
+			if interval, ok := interval.Clip(dayStart, dayEnd); ok {
+				fixed += interval.End.Sub(interval.Start)
+			}
+		}
+	}
+
+	day := dayStart.Format("2006-01-02")
+	if target < fixed {
+		return nil, localErr.Invalid(fmt.Sprintf("%s on %s can't be less than %s tracked by timer, imported, billed or crossing midnight", task.Name, day, fixed))
+	}
+
+	delta := target - fixed - adjustable
+
+	if delta > 0 {
+		// Running timers take the time up to now.
+		now := time.Now()
+		busy := make([]model.Interval, 0, len(tracks)+len(running))
+		for _, track := range tracks {
+			busy = append(busy, model.Interval{Start: track.StartedAt, End: *track.EndedAt})
+		}
+
+		for _, track := range running {
+			busy = append(busy, model.Interval{Start: track.StartedAt, End: now})
+		}
+
+		startedAt := firstGap(busy, dayStart.Add(timesheetDayStart), delta)
+		endedAt := startedAt.Add(delta)
+		if endedAt.After(dayEnd) {
+			return nil, localErr.Invalid(fmt.Sprintf("%s on %s doesn't fit between the sessions of the day", task.Name, day))
+		}
+
+		if endedAt.After(now) {
+			for _, track := range running {
+				if track.StartedAt.Before(endedAt) {
+					return nil, localErr.Conflict(fmt.Sprintf("%s on %s doesn't fit before the running timer, stop it first", task.Name, day), running)
+				}
+			}
+
+			return nil, localErr.Invalid(fmt.Sprintf("%s on %s: time entry can't be in the future", task.Name, day))
+		}
+
+		track := model.TaskTrack{
+			TaskID:    task.ID,
+			StartedAt: startedAt,
+			EndedAt:   &endedAt,
+			Source:    model.TrackSourceManual,
+			Base:      model.Base{ID: uuid.New()},
+		}
+
+		err := s.insertClosedTrack(tx, task, &track)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(tracks, track), nil
+	}
+
+	// The time is taken off the latest sessions first.
+	sort.Slice(manual, func(i, j int) bool {
+		return tracks[manual[i]].StartedAt.After(tracks[manual[j]].StartedAt)
+	})
+
+	deleted := make(map[uuid.UUID]bool)
+	for _, i := range manual {
+		if delta >= 0 {
+			break
+		}
+
+		track := &tracks[i]
+		revision := track.Revision(model.TrackActionTimesheet, nil)
+		err := tx.Create(&revision).Error
+		if err != nil {
+			return nil, err
+		}
+
+		if *track.Time <= -delta {
+			delta += *track.Time
+
+			err = tx.Delete(track).Error
+			if err != nil {
+				return nil, err
+			}
+
+			err = refreshOverlaps(tx, task.UserID, track.StartedAt, *track.EndedAt)
+			if err != nil {
+				return nil, err
+			}
+
+			deleted[track.ID] = true
+			continue
+		}
+
+		track.SetPeriod(track.StartedAt, track.EndedAt.Add(delta))
+		delta = 0
+
+		err = saveTrack(tx, track)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	kept := tracks[:0]
+	for _, track := range tracks {
+		if !deleted[track.ID] {
+			kept = append(kept, track)
+		}
+	}
+
+	return kept, nil
+}
+
+// firstGap returns the earliest start from on of a period of length that doesn't intersect the busy
+// intervals.
+func firstGap(busy []model.Interval, from time.Time, length time.Duration) time.Time {
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].Start.Before(busy[j].Start)
+	})
+
+	for _, interval := range busy {
+		if !interval.Start.Before(from.Add(length)) {
+			break
+		}
+
+		if interval.End.After(from) {
+			from = interval.End
+		}
+	}
+
+	return from
+}
+
+// timesheetWeek returns the location of the days of the timesheet and the beginning of its week.
+func timesheetWeek(request model.TimesheetRequest) (*time.Location, time.Time, error) {
+	location, err := time.LoadLocation(request.Timezone)
+	if err != nil {
+		return nil, time.Time{}, localErr.Invalid("unknown timezone " + request.Timezone)
+	}
+
+	monday, err := model.ParseISOWeek(request.Week, location)
+	if err != nil {
+		return nil, time.Time{}, localErr.Invalid(err.Error())
+	}
+
+	return location, monday, nil
+}
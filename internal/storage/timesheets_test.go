@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"testing"
+	"time"
+	"timeTracker/internal/model"
+)
+
+func TestFirstGap(t *testing.T) {
+	day := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	at := func(hour int, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	span := func(start time.Time, end time.Time) model.Interval {
+		return model.Interval{Start: start, End: end}
+	}
+
+	tests := []struct {
+		name   string
+		busy   []model.Interval
+		length time.Duration
+		start  time.Time
+	}{
+		{"free day", nil, time.Hour, at(9, 0)},
+		{"before a session", []model.Interval{span(at(10, 0), at(11, 0))}, time.Hour, at(9, 0)},
+		{"after a session", []model.Interval{span(at(9, 30), at(11, 0))}, time.Hour, at(11, 0)},
+		{"crossing midnight into the day", []model.Interval{span(at(-2, 0), at(9, 45))}, time.Hour, at(9, 45)},
+		{"gap too short", []model.Interval{span(at(11, 0), at(12, 0)), span(at(8, 0), at(10, 30))}, time.Hour, at(12, 0)},
+		{"gap long enough", []model.Interval{span(at(12, 0), at(13, 0)), span(at(8, 0), at(10, 30))}, time.Hour, at(10, 30)},
+		{"session inside another", []model.Interval{span(at(9, 0), at(12, 0)), span(at(10, 0), at(11, 0))}, time.Hour, at(12, 0)},
+		{"running timer", []model.Interval{span(at(8, 0), at(15, 0))}, time.Hour, at(15, 0)},
+		{"crossing midnight out of the day", []model.Interval{span(at(22, 0), at(26, 0)), span(at(9, 0), at(21, 30))}, time.Hour, at(26, 0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := firstGap(test.busy, at(9, 0), test.length)
+			if !start.Equal(test.start) {
+				t.Errorf("firstGap() = %s, want %s", start, test.start)
+			}
+		})
+	}
+}
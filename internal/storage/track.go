@@ -3,55 +3,1050 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"strings"
 	"time"
+	"timeTracker/config"
+	localErr "timeTracker/internal/errors"
 	"timeTracker/internal/model"
+	"timeTracker/internal/utils"
 )
 
+// TrackTime starts a new session of the task and moves a to do task in progress. Done and cancelled
+// tasks can't be tracked. Other timers of the task owner are handled according to the timer policy
+// of the config; the ones stopped by SWITCH are returned in AutoStopped.
 func (s *Storage) TrackTime(ctx context.Context, trackModel model.TaskTrack) (model.TaskTrack, error) {
-	var savedModel model.TaskTrack
-	err := s.db.Where("task_id = ?", trackModel.TaskID).First(&savedModel).Error
+	task, err := s.GetTask(ctx, trackModel.TaskID)
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
 
-	if err != nil && err.Error() == "record not found" {
-		err = s.db.Create(&trackModel).Error
-		s.db.Save(&trackModel)
+	err = checkTrackable(task)
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
 
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Starts of the same user are serialized, so the policy can't be bypassed by parallel requests.
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", task.UserID).First(&model.User{}).Error
 		if err != nil {
-			return model.TaskTrack{}, err
+			return err
 		}
-	} else {
-		return model.TaskTrack{}, errors.New("task already been started")
+
+		var runningTracks []model.TaskTrack
+		err = tx.Joins("JOIN tasks ON tasks.id = task_tracks.task_id").
+			Where("tasks.user_id = ? AND task_tracks.ended_at IS NULL", task.UserID).
+			Preload("Pauses").Find(&runningTracks).Error
+
+		if err != nil {
+			return err
+		}
+
+		for _, runningTrack := range runningTracks {
+			if runningTrack.TaskID == trackModel.TaskID {
+				return localErr.Conflict("task already been started", nil)
+			}
+		}
+
+		if len(runningTracks) > 0 {
+			switch s.config.TimerPolicy {
+			case config.TIMER_POLICY_REJECT:
+				return localErr.Conflict("another timer is already running", runningTracks)
+			case config.TIMER_POLICY_SWITCH:
+				now := time.Now()
+				for _, runningTrack := range runningTracks {
+					runningTrack.Close(now)
+
+					err = saveTrack(tx, &runningTrack)
+					if err != nil {
+						return err
+					}
+
+					trackModel.AutoStopped = append(trackModel.AutoStopped, runningTrack)
+				}
+			}
+		}
+
+		if task.Status == model.TaskStatusTodo {
+			err = tx.Model(&task).Update("status", model.TaskStatusInProgress).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		trackModel.StartedAt = time.Now()
+		trackModel.Source = model.TrackSourceTimer
+		trackModel.Billable = task.Billable
+
+		return saveTrack(tx, &trackModel)
+	})
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return trackModel, nil
+}
+
+// AddTrack creates a closed session with the explicit times of trackModel. Done and cancelled
+// tasks can't get sessions.
+func (s *Storage) AddTrack(ctx context.Context, trackModel model.TaskTrack) (model.TaskTrack, error) {
+	task, err := s.GetTask(ctx, trackModel.TaskID)
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	if trackModel.EndedAt == nil || !trackModel.EndedAt.After(trackModel.StartedAt) {
+		return model.TaskTrack{}, localErr.Invalid("ended_at must be after started_at")
+	}
+
+	if trackModel.EndedAt.After(time.Now()) {
+		return model.TaskTrack{}, localErr.Invalid("time entry can't be in the future")
+	}
+
+	trackModel.Source = model.TrackSourceManual
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.insertClosedTrack(tx, task, &trackModel)
+	})
+
+	if err != nil {
+		return model.TaskTrack{}, err
 	}
 
 	return trackModel, nil
 }
 
-func (s *Storage) StopTrackTime(ctx context.Context, taskId uuid.UUID) (model.TaskTrack, error) {
+// errImportPreview rolls back the transaction of an import preview.
+var errImportPreview = errors.New("import preview")
+
+// ImportTracks creates a closed session of the task for each event, as AddTrack does, with the
+// summary of the event as its note. Events that end in the future and events the owner of the task
+// imported before, by their UID, are skipped. The UIDs of deleted and merged sessions count too,
+// so that importing the same file again never adds their time twice. Each event lists the sessions
+// it overlaps; with the REJECT overlap policy such events are skipped instead of failing the whole
+// import. In a preview the sessions are checked but not stored.
+func (s *Storage) ImportTracks(ctx context.Context, request model.ImportTracksRequest, events []model.ImportEvent) (model.ImportResult, error) {
+	task, err := s.GetTask(ctx, request.TaskID)
+	if err != nil {
+		return model.ImportResult{}, err
+	}
+
+	var result model.ImportResult
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		result = model.ImportResult{Preview: request.Preview, Events: make([]model.ImportedEvent, 0, len(events))}
+
+		uids := make([]string, 0, len(events))
+		for _, event := range events {
+			uids = append(uids, event.UID)
+		}
+
+		var imported []string
+		if len(uids) > 0 {
+			err := tx.Unscoped().Model(&model.TaskTrack{}).
+				Joins("JOIN tasks ON tasks.id = task_tracks.task_id").
+				Where("tasks.user_id = ? AND task_tracks.import_uid IN ?", task.UserID, uids).
+				Pluck("task_tracks.import_uid", &imported).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		seen := make(map[string]bool, len(imported))
+		for _, uid := range imported {
+			seen[uid] = true
+		}
+
+		now := time.Now()
+		for _, event := range events {
+			outcome := model.ImportedEvent{ImportEvent: event}
+
+			switch {
+			case seen[event.UID]:
+				outcome.Status = model.ImportStatusImported
+			case event.EndedAt.After(now):
+				outcome.Status = model.ImportStatusFuture
+			default:
+				track := model.TaskTrack{
+					TaskID:    task.ID,
+					StartedAt: event.StartedAt,
+					Source:    model.TrackSourceImport,
+					Note:      event.Summary,
+					ImportUID: event.UID,
+					Base:      model.Base{ID: uuid.New()},
+				}
+				track.Close(event.EndedAt)
+
+				overlaps, err := overlappingTracks(tx, task.UserID, &track)
+				if err != nil {
+					return err
+				}
+
+				for _, overlap := range overlaps {
+					outcome.Overlaps = append(outcome.Overlaps, overlap.ID)
+				}
+
+				if len(overlaps) > 0 && s.config.OverlapPolicy == config.OVERLAP_POLICY_REJECT {
+					outcome.Status = model.ImportStatusOverlap
+					break
+				}
+
+				err = s.insertClosedTrack(tx, task, &track)
+				if err != nil {
+					return err
+				}
+
+				outcome.Status = model.ImportStatusCreated
+				if request.Preview {
+					outcome.Status = model.ImportStatusNew
+				} else {
+					outcome.TrackID = &track.ID
+				}
+			}
+
+			if outcome.Status == model.ImportStatusCreated || outcome.Status == model.ImportStatusNew {
+				result.Created++
+			} else {
+				result.Skipped++
+			}
+
+			seen[event.UID] = true
+			result.Events = append(result.Events, outcome)
+		}
+
+		if request.Preview {
+			return errImportPreview
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errImportPreview) {
+		return model.ImportResult{}, err
+	}
+
+	return result, nil
+}
+
+// insertClosedTrack stores a new closed session of the task after checking it against the overlap
+// policy. The session is billable when the task is. Done and cancelled tasks can't get sessions.
+func (s *Storage) insertClosedTrack(tx *gorm.DB, task model.Task, track *model.TaskTrack) error {
+	err := checkTrackable(task)
+	if err != nil {
+		return err
+	}
+
+	track.Billable = task.Billable
+	track.Close(*track.EndedAt)
+
+	err = s.rejectOverlaps(tx, track)
+	if err != nil {
+		return err
+	}
+
+	return saveTrack(tx, track)
+}
+
+// checkTrackable returns a conflict when the task is done or cancelled, so that no session, timed
+// or entered, is added to it until it is reopened.
+func checkTrackable(task model.Task) error {
+	if task.IsClosed() {
+		return localErr.Conflict(fmt.Sprintf("task is %s, reopen it to track time", task.Status), nil)
+	}
+
+	return nil
+}
+
+// StopTrackTime closes the running session of the task. A non-nil note replaces the note of the session.
+func (s *Storage) StopTrackTime(ctx context.Context, taskId uuid.UUID, note *string) (model.TaskTrack, error) {
 	var savedModel model.TaskTrack
-	err := s.db.Where("task_id = ?", taskId).First(&savedModel).Error
 
-	if err != nil && err.Error() == "record not found" {
-		return model.TaskTrack{}, errors.New("no task with that id")
-	} else {
-		if savedModel.Time != nil {
-			return model.TaskTrack{}, errors.New("task already been ended")
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		savedModel, err = s.findOpenTrack(ctx, tx, taskId)
+		if err != nil {
+			return err
+		}
+
+		if note != nil {
+			savedModel.Note = *note
+		}
+
+		savedModel.Close(time.Now())
+
+		return saveTrack(tx, &savedModel)
+	})
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return savedModel, nil
+}
+
+func (s *Storage) PauseTrackTime(ctx context.Context, taskId uuid.UUID) (model.TaskTrack, error) {
+	var savedModel model.TaskTrack
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		savedModel, err = s.findOpenTrack(ctx, tx, taskId)
+		if err != nil {
+			return err
+		}
+
+		if savedModel.RunningPause() != nil {
+			return localErr.Conflict("task already been paused", nil)
+		}
+
+		savedModel.Pauses = append(savedModel.Pauses, model.TaskTrackPause{
+			TaskTrackID: savedModel.ID,
+			PausedAt:    time.Now(),
+			Base:        model.Base{ID: uuid.New()},
+		})
+		savedModel.Recalculate()
+
+		return saveTrack(tx, &savedModel)
+	})
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return savedModel, nil
+}
+
+func (s *Storage) ResumeTrackTime(ctx context.Context, taskId uuid.UUID) (model.TaskTrack, error) {
+	var savedModel model.TaskTrack
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		savedModel, err = s.findOpenTrack(ctx, tx, taskId)
+		if err != nil {
+			return err
+		}
+
+		pause := savedModel.RunningPause()
+		if pause == nil {
+			return localErr.Conflict("task is not paused", nil)
+		}
+
+		now := time.Now()
+		pause.ResumedAt = &now
+		savedModel.Recalculate()
+
+		return saveTrack(tx, &savedModel)
+	})
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return savedModel, nil
+}
+
+// UpdateTrack moves the bounds of a closed session. The previous values are kept in a revision.
+func (s *Storage) UpdateTrack(ctx context.Context, trackModel model.TaskTrack) (model.TaskTrack, error) {
+	if trackModel.EndedAt == nil || !trackModel.EndedAt.After(trackModel.StartedAt) {
+		return model.TaskTrack{}, localErr.Invalid("ended_at must be after started_at")
+	}
+
+	if trackModel.EndedAt.After(time.Now()) {
+		return model.TaskTrack{}, localErr.Invalid("time entry can't be in the future")
+	}
+
+	var savedModel model.TaskTrack
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		savedModel, err = findClosedTrack(tx, trackModel.ID)
+		if err != nil {
+			return err
+		}
+
+		revision := savedModel.Revision(model.TrackActionEdit, nil)
+		err = tx.Create(&revision).Error
+		if err != nil {
+			return err
+		}
+
+		savedModel.SetPeriod(trackModel.StartedAt, *trackModel.EndedAt)
+
+		err = s.rejectOverlaps(tx, &savedModel)
+		if err != nil {
+			return err
+		}
+
+		return saveTrack(tx, &savedModel)
+	})
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return savedModel, nil
+}
+
+// SplitTrack cuts a closed session in two at the instant at. The second part is moved to the task
+// taskID, if it is not uuid.Nil, which can't be done or cancelled, and is billable when that task
+// is. Both parts keep the tags of the session. The previous values are kept in a revision.
+func (s *Storage) SplitTrack(ctx context.Context, trackID uuid.UUID, at time.Time, taskID uuid.UUID) ([]model.TaskTrack, error) {
+	var tracks []model.TaskTrack
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		savedModel, err := findClosedTrack(tx, trackID)
+		if err != nil {
+			return err
+		}
+
+		if !at.After(savedModel.StartedAt) || !at.Before(*savedModel.EndedAt) {
+			return localErr.Invalid("split instant must be inside of the session")
+		}
+
+		secondID := uuid.New()
+		revision := savedModel.Revision(model.TrackActionSplit, &secondID)
+		second := savedModel.Split(at, secondID)
+
+		if taskID != uuid.Nil && taskID != savedModel.TaskID {
+			task, err := s.sameOwnerTask(ctx, savedModel.TaskID, taskID)
+			if err != nil {
+				return err
+			}
+
+			err = checkTrackable(task)
+			if err != nil {
+				return err
+			}
+
+			second.TaskID = taskID
+			second.Billable = task.Billable
 		}
-		now := time.Now().Sub(savedModel.CreatedAt)
-		savedModel.Time = &now
-		err = s.db.Save(&savedModel).Error
 
+		err = tx.Create(&revision).Error
 		if err != nil {
+			return err
+		}
+
+		err = saveTrack(tx, &savedModel)
+		if err != nil {
+			return err
+		}
+
+		err = saveTrack(tx, &second)
+		if err != nil {
+			return err
+		}
+
+		if len(savedModel.Tags) > 0 {
+			err = tx.Model(&second).Association("Tags").Append(savedModel.Tags)
+			if err != nil {
+				return err
+			}
+		}
+
+		tracks = []model.TaskTrack{savedModel, second}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
+}
+
+// MergeTracks joins closed sessions of the same task into the earliest of them. Sessions of the task
+// that are not merged can't lie between them. The merged sessions are deleted and their tags are
+// added to the remaining one. The previous values of all of them are kept in revisions.
+func (s *Storage) MergeTracks(ctx context.Context, trackIDs []uuid.UUID) (model.TaskTrack, error) {
+	var merged model.TaskTrack
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var tracks []model.TaskTrack
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", trackIDs).
+			Order("started_at").Preload("Pauses").Preload("Tags").Find(&tracks).Error
+
+		if err != nil {
+			return err
+		}
+
+		if len(tracks) != len(trackIDs) {
+			return localErr.NotFound("no session with that id")
+		}
+
+		for i, track := range tracks {
+			if track.EndedAt == nil {
+				return localErr.Conflict("session is still running", nil)
+			}
+
+			err = checkNotBilled(&track)
+			if err != nil {
+				return err
+			}
+
+			if track.TaskID != tracks[0].TaskID {
+				return localErr.Invalid("only sessions of the same task can be merged")
+			}
+
+			if i > 0 && track.StartedAt.Before(*tracks[i-1].EndedAt) {
+				return localErr.Invalid("sessions overlap")
+			}
+		}
+
+		first := tracks[0]
+		last := tracks[len(tracks)-1]
+
+		var between int64
+		err = tx.Model(&model.TaskTrack{}).
+			Where("task_id = ? AND id NOT IN ? AND started_at < ? AND (ended_at IS NULL OR ended_at > ?)", first.TaskID, trackIDs, last.EndedAt, first.StartedAt).
+			Count(&between).Error
+
+		if err != nil {
+			return err
+		}
+
+		if between > 0 {
+			return localErr.Invalid("sessions are not adjacent")
+		}
+
+		merged = first
+		for _, track := range tracks[1:] {
+			absorbedID := track.ID
+			revisions := []model.TaskTrackRevision{
+				merged.Revision(model.TrackActionMerge, &absorbedID),
+				track.Revision(model.TrackActionMerge, &merged.ID),
+			}
+
+			err = tx.Create(&revisions).Error
+			if err != nil {
+				return err
+			}
+
+			merged.Merge(track)
+
+			err = tx.Where("task_track_id = ?", track.ID).Delete(&model.TaskTrackPause{}).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Delete(&track).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.rejectOverlaps(tx, &merged)
+		if err != nil {
+			return err
+		}
+
+		err = saveTrack(tx, &merged)
+		if err != nil {
+			return err
+		}
+
+		for _, track := range tracks[1:] {
+			if len(track.Tags) > 0 {
+				err = tx.Model(&merged).Association("Tags").Append(track.Tags)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return merged, nil
+}
+
+func (s *Storage) GetTrackRevisions(ctx context.Context, trackID uuid.UUID) ([]model.TaskTrackRevision, error) {
+	var revisions []model.TaskTrackRevision
+
+	err := s.db.Where("task_track_id = ?", trackID).Order("created_at").Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// SetTrackBillable marks the session as billable or not, unless it is already billed.
+func (s *Storage) SetTrackBillable(ctx context.Context, trackID uuid.UUID, billable bool) (model.TaskTrack, error) {
+	var track model.TaskTrack
+	err := s.db.Where("id = ?", trackID).First(&track).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.TaskTrack{}, localErr.NotFound("no session with that id")
+	}
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	err = checkNotBilled(&track)
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	err = s.db.Model(&track).Update("billable", billable).Error
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return track, nil
+}
+
+// SetTrackNote replaces the note of the session, unless it is already billed. The previous note is
+// kept in a revision.
+func (s *Storage) SetTrackNote(ctx context.Context, trackID uuid.UUID, note string) (model.TaskTrack, error) {
+	var track model.TaskTrack
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", trackID).First(&track).Error
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return localErr.NotFound("no session with that id")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = checkNotBilled(&track)
+		if err != nil {
+			return err
+		}
+
+		revision := track.Revision(model.TrackActionNote, nil)
+		err = tx.Create(&revision).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&track).Update("note", note).Error
+	})
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return track, nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (s *Storage) GetTracks(ctx context.Context, filters model.TrackFilter, pagination utils.Pagination) ([]model.TaskTrack, error) {
+	var tracks []model.TaskTrack
+
+	query := s.db.Model(&model.TaskTrack{}).Joins("JOIN tasks ON tasks.id = task_tracks.task_id AND tasks.deleted_at IS NULL")
+	query = trackFilters(query, filters)
+
+	if !filters.FromFilter.IsZero() {
+		query = query.Where("(task_tracks.ended_at IS NULL OR task_tracks.ended_at > ?)", filters.FromFilter)
+	}
+
+	if !filters.ToFilter.IsZero() {
+		query = query.Where("task_tracks.started_at < ?", filters.ToFilter)
+	}
+
+	err := query.Scopes(utils.Paginate(tracks, &pagination, query.Session(&gorm.Session{}))).Preload("Pauses").Preload("Tags").Find(&tracks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
+}
+
+// trackFilters limits the query on task_tracks joined with tasks to the sessions of the task, of the
+// user and with the note of the filters.
+func trackFilters(query *gorm.DB, filters model.TrackFilter) *gorm.DB {
+	if filters.TaskIDFilter != uuid.Nil {
+		query = query.Where("task_tracks.task_id = ?", filters.TaskIDFilter)
+	}
+
+	if filters.UserIDFilter != uuid.Nil {
+		query = query.Where("tasks.user_id = ?", filters.UserIDFilter)
+	}
+
+	if filters.NoteFilter != "" {
+		query = query.Where("task_tracks.note ILIKE ?", "%"+likeEscaper.Replace(filters.NoteFilter)+"%")
+	}
+
+	return query
+}
+
+// CalcTime sums the closed sessions selected by the request per task, per project or per client in
+// the database. Sessions of tasks without a project or a client are left out of the project or the
+// client totals. Sessions crossing the boundaries of the period are cut to their part inside it.
+// Per task, Total also includes the time of the subtasks, and parent tasks without own sessions
+// are added. The totals are sorted by Total in descending order.
+func (s *Storage) CalcTime(ctx context.Context, request model.CalcTimeRequest) ([]model.TimeTotal, error) {
+	var rows []model.TimeTotal
+
+	columns := periodColumns(request.From, request.To)
+
+	idColumn, nameColumn := "task_tracks.task_id", "tasks.name"
+	query := calcTimeTracks(s.db, request)
+
+	switch request.GroupBy {
+	case model.GroupByProject:
+		idColumn, nameColumn = "tasks.project_id", "projects.name"
+		query = query.Where("tasks.project_id IS NOT NULL")
+	case model.GroupByClient:
+		idColumn, nameColumn = "projects.client_id", "clients.name"
+		query = query.Joins("JOIN clients ON clients.id = projects.client_id")
+	}
+
+	query = query.Select(fmt.Sprintf("%s AS id, %s AS name, SUM(%s)::bigint AS time, COUNT(*) AS sessions_count, "+
+		"MIN(%s) AS first_started_at, MAX(%s) AS last_ended_at, SUM(%s)::bigint AS pauses_count, SUM(%s)::bigint AS paused_time",
+		idColumn, nameColumn, columns.time, columns.startedAt, columns.endedAt, columns.pausesCount, columns.pausedTime),
+		columns.vars()...).
+		Group(idColumn + ", " + nameColumn)
+
+	err := query.Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uuid.UUID]*model.TimeTotal, len(rows))
+	for i := range rows {
+		totals[rows[i].ID] = &rows[i]
+	}
+
+	if request.GroupBy == model.GroupByProject || request.GroupBy == model.GroupByClient {
+		for _, total := range totals {
+			total.Total = total.Time
+		}
+	} else {
+		err = s.rollUpSubtasks(totals)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]model.TimeTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// calcTimeTracks selects the closed sessions of the calc-time request, joined with their tasks and
+// projects.
+func calcTimeTracks(db *gorm.DB, request model.CalcTimeRequest) *gorm.DB {
+	query := closedTracks(db, request.From, request.To).
+		Joins("LEFT JOIN projects ON projects.id = tasks.project_id")
+
+	if request.ID != uuid.Nil {
+		query = query.Where("tasks.user_id = ?", request.ID)
+	}
+
+	if request.ProjectID != uuid.Nil {
+		query = query.Where("tasks.project_id = ?", request.ProjectID)
+	}
+
+	if request.ClientID != uuid.Nil {
+		query = query.Where("projects.client_id = ?", request.ClientID)
+	}
+
+	if request.Status != "" {
+		query = query.Where("tasks.status = ?", request.Status)
+	}
+
+	query = tagsCondition(query, request.Tags, false)
+	query = tagsCondition(query, request.ExcludeTags, true)
+
+	return query
+}
+
+// trackColumns are the SQL expressions of the bounds, the active time, and the pauses of a closed
+// session cut to a period. The expressions refer to the period by the named args, which are nil
+// when there is no period.
+type trackColumns struct {
+	startedAt   string
+	endedAt     string
+	time        string
+	pausesCount string
+	pausedTime  string
+	args        map[string]interface{}
+}
+
+// periodColumns returns the expressions of the part of a closed session within [from, to]. A zero
+// from or to leaves the period open on that side, and the stored values are used when both are.
+func periodColumns(from time.Time, to time.Time) trackColumns {
+	columns := trackColumns{
+		startedAt:   "task_tracks.started_at",
+		endedAt:     "task_tracks.ended_at",
+		time:        "task_tracks.time",
+		pausesCount: "task_tracks.pauses_count",
+		pausedTime:  "task_tracks.paused_time",
+	}
+
+	if !from.IsZero() {
+		columns.startedAt = "GREATEST(task_tracks.started_at, @from)"
+	}
+
+	if !to.IsZero() {
+		columns.endedAt = "LEAST(task_tracks.ended_at, @to)"
+	}
+
+	if !from.IsZero() || !to.IsZero() {
+		columns.args = map[string]interface{}{"from": from, "to": to}
+
+		pauses := fmt.Sprintf("FROM task_track_pauses WHERE task_track_pauses.task_track_id = task_tracks.id AND "+
+			"task_track_pauses.deleted_at IS NULL AND task_track_pauses.resumed_at > %s AND task_track_pauses.paused_at < %s",
+			columns.startedAt, columns.endedAt)
+		columns.pausedTime = fmt.Sprintf("COALESCE((SELECT SUM(ROUND(EXTRACT(EPOCH FROM LEAST(task_track_pauses.resumed_at, %s) - "+
+			"GREATEST(task_track_pauses.paused_at, %s)) * 1000000000)) %s), 0)", columns.endedAt, columns.startedAt, pauses)
+		columns.pausesCount = fmt.Sprintf("(SELECT COUNT(*) %s)", pauses)
+		columns.time = fmt.Sprintf("ROUND(EXTRACT(EPOCH FROM %s - %s) * 1000000000) - %s",
+			columns.endedAt, columns.startedAt, columns.pausedTime)
+	}
+
+	return columns
+}
+
+// vars returns the args to select the expressions with. There are none without a period, as GORM
+// would bind a map that no expression refers to as a positional parameter.
+func (c trackColumns) vars() []interface{} {
+	if c.args == nil {
+		return nil
+	}
+
+	return []interface{}{c.args}
+}
+
+// closedTracks selects the closed sessions of the tasks that are not deleted and intersect
+// [from, to]. A zero from or to leaves the period open on that side.
+func closedTracks(db *gorm.DB, from time.Time, to time.Time) *gorm.DB {
+	query := db.Table("task_tracks").
+		Joins("JOIN tasks ON tasks.id = task_tracks.task_id").
+		Where("task_tracks.time IS NOT NULL AND task_tracks.deleted_at IS NULL AND tasks.deleted_at IS NULL")
+
+	if !from.IsZero() {
+		query = query.Where("task_tracks.ended_at > ?", from)
+	}
+
+	if !to.IsZero() {
+		query = query.Where("task_tracks.started_at < ?", to)
+	}
+
+	return query
+}
+
+// rollUpSubtasks sets Total of every task to its own time plus the time of its subtasks. The
+// ancestors of the tasks are added to totals when they are missing.
+func (s *Storage) rollUpSubtasks(totals map[uuid.UUID]*model.TimeTotal) error {
+	if len(totals) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]uuid.UUID, 0, len(totals))
+	for taskID := range totals {
+		taskIDs = append(taskIDs, taskID)
+	}
+
+	var tasks []struct {
+		ID       uuid.UUID
+		Name     string
+		ParentID *uuid.UUID
+	}
+
+	err := s.db.Raw(`WITH RECURSIVE ancestors AS (
+			SELECT id, name, parent_id FROM tasks WHERE id IN ?
+			UNION
+			SELECT tasks.id, tasks.name, tasks.parent_id FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+			WHERE tasks.deleted_at IS NULL
+		) SELECT id, name, parent_id FROM ancestors`, taskIDs).Scan(&tasks).Error
+
+	if err != nil {
+		return err
+	}
+
+	parents := make(map[uuid.UUID]uuid.UUID)
+	names := make(map[uuid.UUID]string)
+	for _, task := range tasks {
+		names[task.ID] = task.Name
+		if task.ParentID != nil {
+			parents[task.ID] = *task.ParentID
+		}
+	}
+
+	for _, taskID := range taskIDs {
+		own := totals[taskID].Time
+
+		for id, ok := taskID, true; ok; id, ok = parents[id] {
+			total, found := totals[id]
+			if !found {
+				total = &model.TimeTotal{ID: id, Name: names[id]}
+				totals[id] = total
+			}
+
+			total.Total += own
+		}
+	}
+
+	return nil
+}
+
+// findOpenTrack loads the open session of the task together with its pauses and locks it
+// until the end of the transaction.
+func (s *Storage) findOpenTrack(ctx context.Context, tx *gorm.DB, taskId uuid.UUID) (model.TaskTrack, error) {
+	var savedModel model.TaskTrack
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("task_id = ? AND ended_at IS NULL", taskId).Preload("Pauses").First(&savedModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if _, err := s.GetTask(ctx, taskId); err != nil {
 			return model.TaskTrack{}, err
 		}
+
+		return model.TaskTrack{}, localErr.Conflict("task is not being tracked", nil)
+	}
+
+	if err != nil {
+		return model.TaskTrack{}, err
 	}
 
 	return savedModel, nil
 }
 
-func (s *Storage) CalcTime(ctx context.Context, userID uuid.UUID) ([]model.TaskTrack, error) {
-	var taskTrackModels []model.TaskTrack
+// findClosedTrack loads the closed session together with its pauses and locks it until the end
+// of the transaction. Billed sessions are refused, as they can't be changed.
+func findClosedTrack(tx *gorm.DB, trackID uuid.UUID) (model.TaskTrack, error) {
+	var savedModel model.TaskTrack
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", trackID).Preload("Pauses").Preload("Tags").First(&savedModel).Error
 
-	s.db.Raw("SELECT tt.* FROM task_tracks as tt JOIN tasks ON tt.task_id = tasks.id WHERE tasks.user_id = ? ORDER BY time DESC", userID).Scan(&taskTrackModels)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.TaskTrack{}, localErr.NotFound("no session with that id")
+	}
+
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	if savedModel.EndedAt == nil {
+		return model.TaskTrack{}, localErr.Conflict("session is still running", nil)
+	}
+
+	err = checkNotBilled(&savedModel)
+	if err != nil {
+		return model.TaskTrack{}, err
+	}
+
+	return savedModel, nil
+}
+
+// sameOwnerTask returns the task otherTaskID after checking that both tasks exist and belong to
+// the same user.
+func (s *Storage) sameOwnerTask(ctx context.Context, taskID uuid.UUID, otherTaskID uuid.UUID) (model.Task, error) {
+	task, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	otherTask, err := s.GetTask(ctx, otherTaskID)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	if task.UserID != otherTask.UserID {
+		return model.Task{}, localErr.Invalid("tasks belong to different users")
+	}
+
+	return otherTask, nil
+}
+
+// saveTrack creates or updates the session and its pauses. Every change of a session,
+// whether it comes from a timer or is entered manually, is saved here. The overlapping flags
+// of the sessions around the old and the new times of the session are recalculated.
+func saveTrack(tx *gorm.DB, track *model.TaskTrack) error {
+	var stored model.TaskTrack
+	err := tx.Where("id = ?", track.ID).Limit(1).Find(&stored).Error
+	if err != nil {
+		return err
+	}
+
+	if track.CreatedAt.IsZero() {
+		err = tx.Omit(clause.Associations).Create(track).Error
+	} else {
+		err = tx.Omit(clause.Associations).Save(track).Error
+	}
+
+	if err != nil {
+		return err
+	}
+
+	pauseIDs := make([]uuid.UUID, 0, len(track.Pauses))
+	for i := range track.Pauses {
+		err = tx.Save(&track.Pauses[i]).Error
+		if err != nil {
+			return err
+		}
+
+		pauseIDs = append(pauseIDs, track.Pauses[i].ID)
+	}
+
+	droppedPauses := tx.Where("task_track_id = ?", track.ID)
+	if len(pauseIDs) > 0 {
+		droppedPauses = droppedPauses.Where("id NOT IN ?", pauseIDs)
+	}
+
+	err = droppedPauses.Delete(&model.TaskTrackPause{}).Error
+	if err != nil {
+		return err
+	}
+
+	var periods []model.Interval
+	for _, saved := range []model.TaskTrack{stored, *track} {
+		if saved.ID != uuid.Nil && saved.EndedAt != nil {
+			periods = append(periods, model.Interval{Start: saved.StartedAt, End: *saved.EndedAt})
+		}
+	}
+
+	if len(periods) == 0 {
+		return nil
+	}
+
+	from, to := periods[0].Start, periods[0].End
+	for _, period := range periods[1:] {
+		if period.Start.Before(from) {
+			from = period.Start
+		}
+
+		if period.End.After(to) {
+			to = period.End
+		}
+	}
+
+	userID, err := trackOwner(tx, track)
+	if err != nil {
+		return err
+	}
+
+	err = refreshOverlaps(tx, userID, from, to)
+	if err != nil {
+		return err
+	}
 
-	return taskTrackModels, nil
+	return tx.Model(&model.TaskTrack{}).Select("overlapping").Where("id = ?", track.ID).Scan(&track.Overlapping).Error
 }
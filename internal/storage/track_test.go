@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+	"timeTracker/internal/model"
+)
+
+func TestCalcTimeArgs(t *testing.T) {
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name    string
+		request model.CalcTimeRequest
+	}{
+		{"no period", model.CalcTimeRequest{ID: uuid.New()}},
+		{"from", model.CalcTimeRequest{ID: uuid.New(), From: from}},
+		{"to", model.CalcTimeRequest{ID: uuid.New(), To: to}},
+		{"period", model.CalcTimeRequest{ID: uuid.New(), From: from, To: to}},
+		{"project without period", model.CalcTimeRequest{ProjectID: uuid.New(), GroupBy: model.GroupByProject}},
+		{"client with period", model.CalcTimeRequest{ClientID: uuid.New(), GroupBy: model.GroupByClient, From: from, To: to}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, statements := dryRunStorage(t)
+
+			_, err := s.CalcTime(context.Background(), test.request)
+			if dryRunError(err) != nil {
+				t.Fatal(err)
+			}
+
+			checkPlaceholders(t, *statements)
+		})
+	}
+}
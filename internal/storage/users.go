@@ -2,7 +2,11 @@ package storage
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"github.com/google/uuid"
+	localErr "timeTracker/internal/errors"
 	"timeTracker/internal/model"
 	"timeTracker/internal/utils"
 )
@@ -30,7 +34,7 @@ func (s *Storage) AddUser(ctx context.Context, user model.User) (model.User, err
 }
 
 func (s *Storage) UpdateUser(ctx context.Context, user model.User) (model.User, error) {
-	err := s.db.Save(&user).Error
+	err := s.db.Omit("calendar_token_hash").Save(&user).Error
 
 	if err != nil {
 		return model.User{}, err
@@ -48,3 +52,47 @@ func (s *Storage) DeleteUser(ctx context.Context, userID uuid.UUID) (bool, error
 
 	return true, nil
 }
+
+// ResetCalendarToken gives the user a new random token of the calendar feed and returns it. Only the
+// hash of the token is stored.
+func (s *Storage) ResetCalendarToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	random := make([]byte, 32)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	token := hex.EncodeToString(random)
+
+	result := s.db.Model(&model.User{}).Where("id = ?", userID).Update("calendar_token_hash", calendarTokenHash(token))
+	if result.Error != nil {
+		return "", result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return "", localErr.NotFound("no user with that id")
+	}
+
+	return token, nil
+}
+
+// GetCalendarUser returns the user with the token of the calendar feed.
+func (s *Storage) GetCalendarUser(ctx context.Context, token string) (model.User, error) {
+	var user model.User
+
+	err := s.db.Where("calendar_token_hash = ?", calendarTokenHash(token)).Limit(1).Find(&user).Error
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if user.ID == uuid.Nil {
+		return model.User{}, localErr.NotFound("no calendar with that token")
+	}
+
+	return user, nil
+}
+
+func calendarTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -41,7 +41,7 @@ func Paginate(value interface{}, pagination *Pagination, db *gorm.DB) func(db *g
 	var totalRows int64
 	db.Model(value).Count(&totalRows)
 	pagination.TotalRows = totalRows
-	totalPages := int(math.Ceil(float64(totalRows) / float64(pagination.Limit)))
+	totalPages := int(math.Ceil(float64(totalRows) / float64(pagination.GetLimit())))
 	pagination.TotalPages = totalPages
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Offset(pagination.GetOffset()).Limit(pagination.GetLimit()).Order(pagination.GetSort())
@@ -1,6 +1,7 @@
 package httputils
 
 import (
+	"encoding/csv"
 	"fmt"
 	"net/http"
 	"time"
@@ -78,3 +79,19 @@ func (s *Sender) JSON(w http.ResponseWriter, statusCode int, v interface{}) erro
 
 	return nil
 }
+
+// HTML renders the template name with v and sends it to the client with w.
+func (s *Sender) HTML(w http.ResponseWriter, statusCode int, name string, v interface{}) error {
+	return s.Render.HTML(w, statusCode, name, v)
+}
+
+// CSV sends the headers of a csv attachment named filename to the client with w and returns the
+// writer of its records. The records go to the client as the writer fills its buffer, the caller
+// flushes it after the last one.
+func (s *Sender) CSV(w http.ResponseWriter, statusCode int, filename string) *csv.Writer {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(statusCode)
+
+	return csv.NewWriter(w)
+}
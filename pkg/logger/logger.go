@@ -9,6 +9,11 @@ import (
 var OutputLog = logrus.New()
 
 func init() {
+	err := os.MkdirAll("logs", 0775)
+	if err != nil {
+		logrus.Fatal("Failed to create the log directory: ", err)
+	}
+
 	file, err := os.OpenFile("logs/api.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0664)
 	if err != nil {
 		logrus.Fatal("Failed to open log file: ", err)